@@ -118,6 +118,12 @@ func DirectedGraphSpec(c gospec.Context, graphCreator func() DirectedGraph) {
 			c.Expect(gr.ArcsCnt(), Equals, 7)
 		})
 		
+		c.Specify("removing arc decreases arrows count", func() {
+			gr.RemoveArc(1, 2)
+			c.Expect(gr.ArcsCnt(), Equals, 6)
+			c.Expect(gr.CheckArc(1, 2), IsFalse)
+		})
+		
 		c.Specify("checking sources", func() {
 			sources := CollectVertexes(gr.GetSources())
 			c.Expect(sources, ContainsExactly, Values(VertexId(4), VertexId(6)))
@@ -165,6 +171,20 @@ func DirectedGraphSpec(c gospec.Context, graphCreator func() DirectedGraph) {
 	})
 }
 
+func DirectedGraphRemoveNodeSpec(c gospec.Context, graphCreator func() DirectedGraph) {
+	gr := graphCreator()
+	ReadDgraphLine(gr, "1>2>3>1")
+	gr.AddArc(2, 2)
+	gr.AddArc(4, 2)
+	gr.AddArc(3, 4)
+	
+	c.Specify("removing node decreases arrows count", func() {
+		gr.RemoveNode(2)
+		c.Expect(gr.ArcsCnt(), Equals, 2)
+		c.Expect(gr.Order(), Equals, 3)
+	})
+}
+
 func TestDirectedGraphSpec(t *testing.T) {
 	r := gospec.NewRunner()
 
@@ -184,5 +204,18 @@ func TestDirectedGraphSpec(t *testing.T) {
 	r.AddNamedSpec("DirectedGraph(MixedMap)", cr(func() DirectedGraph {
 		return DirectedGraph(NewMixedMap())
 	}))
+	
+	// matrices doesn't support nodes removing
+	crRemove := func(graphCreator func() DirectedGraph) func (c gospec.Context) {
+		return func(c gospec.Context){
+			DirectedGraphRemoveNodeSpec(c, graphCreator)
+		}
+	}
+	r.AddNamedSpec("DirectedGraphRemoveNode(DirectedMap)", crRemove(func() DirectedGraph {
+		return DirectedGraph(NewDirectedMap())
+	}))
+	r.AddNamedSpec("DirectedGraphRemoveNode(MixedMap)", crRemove(func() DirectedGraph {
+		return DirectedGraph(NewMixedMap())
+	}))
 	gospec.MainGoTest(r, t)
 }
@@ -91,6 +91,11 @@ func (g *DirectedMap) RemoveNode(node VertexId) {
 		panic(makeError(erx.NewError("Node doesn't exist.")))
 	}
 	
+	g.arcsCnt -= len(g.directArcs[node]) + len(g.reversedArcs[node])
+	if _, ok := g.directArcs[node][node]; ok {
+		// self-loop is in both direct and reversed arcs
+		g.arcsCnt++
+	}
 	g.directArcs[node] = nil, false
 	g.reversedArcs[node] = nil, false
 	for _, connectedVertexes := range g.directArcs {
@@ -151,8 +156,8 @@ func (g *DirectedMap) RemoveArc(from, to VertexId) {
 		panic(makeError(erx.NewError("Tail node doesn't exist.")))
 	}
 	
-	if _, ok = connectedVertexes[to]; ok {
-		panic(makeError(erx.NewError("Head node doesn't exist.")))
+	if _, ok = connectedVertexes[to]; !ok {
+		panic(makeError(erx.NewError("Arc doesn't exist.")))
 	}
 	
 	g.directArcs[from][to] = false, false
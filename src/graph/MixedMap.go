@@ -92,6 +92,14 @@ func (g *MixedMap) RemoveNode(node VertexId) {
 		panic(erx.NewError("Node doesn't exist."))
 	}
 	
+	for _, connType := range g.connections[node] {
+		switch connType {
+			case CT_UNDIRECTED:
+				g.edgesCnt--
+			case CT_DIRECTED, CT_DIRECTED_REVERSED:
+				g.arcsCnt--
+		}
+	}
 	g.connections[node] = nil, false
 	for _, connectedVertexes := range g.connections {
 		connectedVertexes[node] = CT_NONE, false
@@ -148,11 +156,11 @@ func (g *MixedMap) RemoveArc(from, to VertexId) {
 		}
 	}()
 
-	if _, ok := g.connections[from]; ok {
+	if _, ok := g.connections[from]; !ok {
 		panic(erx.NewError("Tail node doesn't exist."))
 	}
 	
-	if _, ok := g.connections[to]; ok {
+	if _, ok := g.connections[to]; !ok {
 		panic(erx.NewError("Head node doesn't exist."))
 	}
 	
@@ -393,6 +401,10 @@ func (g *MixedMap) RemoveEdge(from, to VertexId) {
 		panic(erx.NewError("Second node doesn't exists"))
 	}
 	
+	if dir, ok := g.connections[from][to]; !ok || dir!=CT_UNDIRECTED {
+		panic(erx.NewError("Edge doesn't exist."))
+	}
+	
 	g.connections[from][to] = CT_NONE, false
 	g.connections[to][from] = CT_NONE, false
 	g.edgesCnt--
@@ -49,6 +49,12 @@ func UndirectedGraphSpec(c gospec.Context, graphCreator func() UndirectedGraph)
 			c.Expect(gr.EdgesCnt(), Equals, 1)
 		})
 		
+		c.Specify("removing edge decreases edges count", func() {
+			gr.RemoveEdge(n2, n1)
+			c.Expect(gr.EdgesCnt(), Equals, 0)
+			c.Expect(gr.CheckEdge(n1, n2), IsFalse)
+		})
+		
 		c.Specify("neighbours", func() {
 			c.Expect(CollectVertexes(gr.GetNeighbours(n1)), ContainsExactly, Values(n2))
 			c.Expect(CollectVertexes(gr.GetNeighbours(n2)), ContainsExactly, Values(n1))
@@ -79,6 +79,7 @@ func (g *UndirectedMap) RemoveNode(node VertexId) {
 		panic(makeError(erx.NewError("Node doesn't exist.")))
 	}
 	
+	g.edgesCnt -= len(g.edges[node])
 	g.edges[node] = nil, false
 	for _, connectedVertexes := range g.edges {
 		connectedVertexes[node] = false, false
@@ -135,8 +136,8 @@ func (g *UndirectedMap) RemoveEdge(from, to VertexId) {
 		panic(makeError(erx.NewError("First node doesn't exists")))
 	}
 	
-	if _, ok = connectedVertexes[to]; ok {
-		panic(makeError(erx.NewError("Second node doesn't exists")))
+	if _, ok = connectedVertexes[to]; !ok {
+		panic(makeError(erx.NewError("Edge doesn't exist.")))
 	}
 	
 	g.edges[from][to] = false, false
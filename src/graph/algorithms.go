@@ -30,6 +30,9 @@ func ReduceDirectPaths(og DirectedGraphReader, rg DirectedGraphArcsWriter, stopF
 //
 // Return nodes in topological order. If graph has cycles, then hasCycles==true 
 // and nodes==nil in function result.
+//
+// Sources and accessors are processed in increasing id order, so result is
+// the same for equal graphs.
 func TopologicalSort(gr DirectedGraphReader) (nodes []VertexId, hasCycles bool) {
 	hasCycles = false
 	nodes = make([]VertexId, gr.Order())
@@ -37,7 +40,7 @@ func TopologicalSort(gr DirectedGraphReader) (nodes []VertexId, hasCycles bool)
 	// map of node status. If node doesn't present in map - white color,
 	// node in map with false value - grey color, and with true value - black color
 	status := make(map[VertexId]bool)
-	for source := range gr.GetSources().VertexesIter() {
+	for _, source := range SortedVertexes(gr.GetSources()) {
 		pos, hasCycles = topologicalSortHelper(gr, source, nodes[0:pos], status)
 		if hasCycles {
 			nodes = nil
@@ -62,7 +65,7 @@ func topologicalSortHelper(gr DirectedGraphReader, curNode VertexId, nodes []Ver
 	hasCycles = false
 	status[curNode] = false
 	pos = len(nodes)
-	for accessor := range gr.GetAccessors(curNode).VertexesIter() {
+	for _, accessor := range SortedVertexes(gr.GetAccessors(curNode)) {
 		if isBlack, ok := status[accessor]; ok {
 			if !isBlack {
 				// cycle detected!
@@ -84,6 +87,33 @@ func topologicalSortHelper(gr DirectedGraphReader, curNode VertexId, nodes []Ver
 	return
 }
 
+// Longest path layering of directed acyclic graph
+//
+// Layer of node is the number of arcs in the longest path from any source to
+// it, so sources (and isolated nodes) have layer 0 and every arc goes from
+// lower layer to higher one. Layers are calculated in topological order.
+//
+// If graph has cycles, then nil and false are returned.
+func LongestPathLayers(gr DirectedGraphReader) (map[VertexId]int, bool) {
+	nodes, hasCycles := TopologicalSort(gr)
+	if hasCycles {
+		return nil, false
+	}
+	
+	layers := make(map[VertexId]int, len(nodes))
+	for _, node := range nodes {
+		layers[node] = 0
+	}
+	for _, node := range nodes {
+		for next := range gr.GetAccessors(node).VertexesIter() {
+			if layers[next] < layers[node] + 1 {
+				layers[next] = layers[node] + 1
+			}
+		}
+	}
+	return layers, true
+}
+
 // Split mixed graph to independed subraphs.
 //
 // Each result subgraph contain only those vertexes, which are connected, and
@@ -269,3 +299,247 @@ func splitGraphToIndependentSubgraphs_helper(node VertexId, color int, gr OutNei
 	}
 	return
 }
+
+// Immediate dominators of directed graph nodes with Cooper-Harvey-Kennedy algorithm
+//
+// Node d dominates node n, if every path from entry to n goes through d.
+// Immediate dominator of n is the closest to n of it's strict dominators.
+// Nodes are processed in reverse postorder of depth-first search from entry,
+// and dominators are refined by intersecting predecessors dominators till
+// nothing changes.
+//
+// Returns map from each node, reachable from entry, (except entry itself) to
+// it's immediate dominator. Unreachable nodes are absent in result.
+func DominatorTree(gr DirectedGraphReader, entry VertexId) map[VertexId]VertexId {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Build dominator tree", e)
+			err.AddV("entry", entry)
+			panic(err)
+		}
+	}()
+	
+	if !gr.CheckNode(entry) {
+		err := erx.NewError("Entry node doesn't exist.")
+		panic(err)
+	}
+	
+	postorder := make([]VertexId, 0, 10)
+	DepthFirstWalk(NewDgraphOutNeighboursExtractor(gr), entry, nil, func(node VertexId) {
+		postorder = append(postorder, node)
+	})
+	postorderPos := make(map[VertexId]int, len(postorder))
+	for pos, node := range postorder {
+		postorderPos[node] = pos
+	}
+	
+	intersect := func(idom map[VertexId]VertexId, node1, node2 VertexId) VertexId {
+		for node1!=node2 {
+			for postorderPos[node1]<postorderPos[node2] {
+				node1 = idom[node1]
+			}
+			for postorderPos[node2]<postorderPos[node1] {
+				node2 = idom[node2]
+			}
+		}
+		return node1
+	}
+	
+	idom := map[VertexId]VertexId{entry:entry}
+	changed := true
+	for changed {
+		changed = false
+		// reverse postorder without entry, which is the last one in postorder
+		for pos:=len(postorder)-2; pos>=0; pos-- {
+			node := postorder[pos]
+			newIdom := node
+			found := false
+			for pred := range gr.GetPredecessors(node).VertexesIter() {
+				if _, ok := idom[pred]; !ok {
+					// unreachable or not processed yet
+					continue
+				}
+				if !found {
+					newIdom = pred
+					found = true
+				} else {
+					newIdom = intersect(idom, pred, newIdom)
+				}
+			}
+			if oldIdom, ok := idom[node]; !ok || oldIdom!=newIdom {
+				idom[node] = newIdom
+				changed = true
+			}
+		}
+	}
+	
+	idom[entry] = 0, false
+	return idom
+}
+
+// Lowest common ancestors structure for rooted directed tree (arborescence)
+//
+// Built with binary lifting: for each vertex 2^k-th ancestors are stored, so
+// preprocessing takes O(V*log(V)) time and memory, and each query takes
+// O(log(V)) time.
+type LCAStructure struct {
+	depth map[VertexId]int
+	// up[k][node] is 2^k-th ancestor of node, root is ancestor of itself
+	up []map[VertexId]VertexId
+}
+
+// Build lowest common ancestors structure for arborescence with given root
+//
+// Graph must be an arborescence (see IsArborescence), otherwise function
+// panics, because in DAG vertex may have several parents and lowest common
+// ancestor isn't unique.
+func NewLCA(gr DirectedGraphReader, root VertexId) *LCAStructure {
+	if !IsArborescence(gr, root) {
+		err := erx.NewError("Graph isn't an arborescence with given root.")
+		err.AddV("root", root)
+		panic(err)
+	}
+	
+	parent := map[VertexId]VertexId{root:root}
+	depth := map[VertexId]int{root:0}
+	maxDepth := 0
+	queue := []VertexId{root}
+	for len(queue)>0 {
+		curNode := queue[0]
+		queue = queue[1:]
+		for nextNode := range gr.GetAccessors(curNode).VertexesIter() {
+			parent[nextNode] = curNode
+			depth[nextNode] = depth[curNode] + 1
+			if depth[nextNode]>maxDepth {
+				maxDepth = depth[nextNode]
+			}
+			queue = append(queue, nextNode)
+		}
+	}
+	
+	l := &LCAStructure{
+		depth: depth,
+		up: []map[VertexId]VertexId{parent},
+	}
+	for jump:=2; jump<=maxDepth; jump*=2 {
+		prevUp := l.up[len(l.up)-1]
+		curUp := make(map[VertexId]VertexId, len(prevUp))
+		for node, ancestor := range prevUp {
+			curUp[node] = prevUp[ancestor]
+		}
+		l.up = append(l.up, curUp)
+	}
+	return l
+}
+
+// Lowest common ancestor of two vertexes
+//
+// Vertex is ancestor of itself, so if u is ancestor of v, then u is returned.
+// Returns false if any of vertexes isn't in tree.
+func (l *LCAStructure) Query(u, v VertexId) (VertexId, bool) {
+	depthU, okU := l.depth[u]
+	depthV, okV := l.depth[v]
+	if !okU || !okV {
+		return 0, false
+	}
+	if depthU<depthV {
+		u, v = v, u
+		depthU, depthV = depthV, depthU
+	}
+	
+	// lifting u to depth of v
+	for k, diff := 0, depthU-depthV; diff>0; k, diff = k+1, diff/2 {
+		if diff%2==1 {
+			u = l.up[k][u]
+		}
+	}
+	if u==v {
+		return u, true
+	}
+	
+	for k:=len(l.up)-1; k>=0; k-- {
+		if l.up[k][u]!=l.up[k][v] {
+			u = l.up[k][u]
+			v = l.up[k][v]
+		}
+	}
+	return l.up[0][u], true
+}
+
+// Lowest common ancestors for batch of queries with Tarjan offline algorithm
+//
+// Depth-first search from root merges each finished subtree into the set of
+// it's parent, and the set ancestor is the vertex, whose subtree is being
+// processed. So when u is finished and v is already finished, LCA of u and v is
+// the ancestor of v's set. Takes O((V+Q)*alpha(V)) time for Q queries, while
+// LCAStructure needs O(V*log(V)) preprocessing and O(log(V)) per query, but
+// answers queries online.
+//
+// Graph must be an arborescence (see IsArborescence) and all queries vertexes
+// must be in it, otherwise function panics. Answers are returned in queries
+// order.
+func OfflineLCA(gr DirectedGraphReader, root VertexId, queries [][2]VertexId) []VertexId {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Answer lowest common ancestor queries offline", e)
+			err.AddV("root", root)
+			panic(err)
+		}
+	}()
+	
+	if !IsArborescence(gr, root) {
+		panic(erx.NewError("Graph isn't an arborescence with given root."))
+	}
+	// queries indexes for each vertex
+	nodeQueries := make(map[VertexId][]int)
+	for i, query := range queries {
+		for _, node := range query {
+			if !gr.CheckNode(node) {
+				err := erx.NewError("Query vertex isn't in tree.")
+				err.AddV("query", i)
+				err.AddV("vertex", node)
+				panic(err)
+			}
+		}
+		nodeQueries[query[0]] = append(nodeQueries[query[0]], i)
+		if query[1]!=query[0] {
+			nodeQueries[query[1]] = append(nodeQueries[query[1]], i)
+		}
+	}
+	
+	answers := make([]VertexId, len(queries))
+	sets := newVertexesDisjointSets()
+	// ancestor of each set, indexed by set representative
+	ancestor := map[VertexId]VertexId{sets.Find(root):root}
+	finished := make(map[VertexId]bool)
+	stack := []dfsFrame{dfsFrame{node:root, neighbours:CollectVertexes(gr.GetAccessors(root))}}
+	for len(stack)>0 {
+		top := &stack[len(stack)-1]
+		if top.pos<len(top.neighbours) {
+			nextNode := top.neighbours[top.pos]
+			top.pos++
+			ancestor[sets.Find(nextNode)] = nextNode
+			stack = append(stack, dfsFrame{node:nextNode, neighbours:CollectVertexes(gr.GetAccessors(nextNode))})
+			continue
+		}
+		
+		node := top.node
+		finished[node] = true
+		for _, i := range nodeQueries[node] {
+			other := queries[i][0]
+			if other==node {
+				other = queries[i][1]
+			}
+			if finished[other] {
+				answers[i] = ancestor[sets.Find(other)]
+			}
+		}
+		stack = stack[0:len(stack)-1]
+		if len(stack)>0 {
+			parent := stack[len(stack)-1].node
+			sets.Union(parent, node)
+			ancestor[sets.Find(parent)] = parent
+		}
+	}
+	return answers
+}
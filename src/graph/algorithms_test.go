@@ -1,6 +1,7 @@
 package graph
 
 import (
+	"rand"
 	"testing"
 	"github.com/orfjackal/gospec/src/gospec"
 	. "github.com/orfjackal/gospec/src/gospec"
@@ -70,6 +71,15 @@ func TopologicalSortSpec(c gospec.Context) {
 		_, hasCycle := TopologicalSort(gr)
 		c.Expect(hasCycle, IsFalse)
 	})
+	
+	c.Specify("Order is reproducible", func() {
+		gr := generateDirectedGraph1()
+		for i:=0; i<10; i++ {
+			nodes, hasCycle := TopologicalSort(gr)
+			c.Expect(hasCycle, IsFalse)
+			c.Expect(nodes, ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(6), VertexId(3), VertexId(4), VertexId(5)))
+		}
+	})
 }
 
 func SplitGraphToIndependentSubgraphs_mixedSpec(c gospec.Context) {
@@ -124,13 +134,183 @@ func SplitGraphToIndependentSubgraphs_undirectedSpec(c gospec.Context) {
 	})
 }
 
+func LongestPathLayersSpec(c gospec.Context) {
+	c.Specify("Layers of acyclic graph", func() {
+		gr := generateDirectedGraph1()
+		gr.AddNode(7)
+		layers, ok := LongestPathLayers(gr)
+		c.Expect(ok, IsTrue)
+		c.Expect(len(layers), Equals, gr.Order())
+		c.Expect(layers[1], Equals, 0)
+		c.Expect(layers[2], Equals, 1)
+		c.Expect(layers[3], Equals, 2)
+		c.Expect(layers[4], Equals, 3)
+		c.Expect(layers[5], Equals, 4)
+		c.Expect(layers[6], Equals, 2)
+		c.Expect(layers[7], Equals, 0)
+		for conn := range gr.ArcsIter() {
+			c.Expect(layers[conn.Tail] < layers[conn.Head], IsTrue)
+		}
+	})
+	
+	c.Specify("Graph with cycle", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>3>2")
+		layers, ok := LongestPathLayers(gr)
+		c.Expect(ok, IsFalse)
+		c.Expect(layers, IsNil)
+	})
+}
+
+func DominatorTreeSpec(c gospec.Context) {
+	c.Specify("Diamond with back edge", func() {
+		// 1 - entry, 2 -> (3 | 4) -> 5 diamond, 5 -> 2 back edge, 5 -> 6 exit
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>3>5>6")
+		ReadDgraphLine(gr, "2>4>5>2")
+		idom := DominatorTree(gr, 1)
+		c.Expect(len(idom), Equals, 5)
+		c.Expect(idom[2], Equals, VertexId(1))
+		c.Expect(idom[3], Equals, VertexId(2))
+		c.Expect(idom[4], Equals, VertexId(2))
+		c.Expect(idom[5], Equals, VertexId(2))
+		c.Expect(idom[6], Equals, VertexId(5))
+	})
+	
+	c.Specify("Unreachable nodes are omitted", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>3")
+		ReadDgraphLine(gr, "4>3")
+		idom := DominatorTree(gr, 1)
+		c.Expect(len(idom), Equals, 2)
+		c.Expect(idom[3], Equals, VertexId(2))
+		_, ok := idom[4]
+		c.Expect(ok, IsFalse)
+	})
+	
+	c.Specify("Join of loop and direct path", func() {
+		// textbook example: 1->2->3->4, 1->5->4, 4->2 loop
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>3>4>2")
+		ReadDgraphLine(gr, "1>5>4")
+		idom := DominatorTree(gr, 1)
+		c.Expect(idom[2], Equals, VertexId(1))
+		c.Expect(idom[3], Equals, VertexId(2))
+		c.Expect(idom[4], Equals, VertexId(1))
+		c.Expect(idom[5], Equals, VertexId(1))
+	})
+}
+
+func LCASpec(c gospec.Context) {
+	// 1 - root, 2 and 3 subtrees, long path 3>6>7>8>9>10
+	gr := NewDirectedMap()
+	ReadDgraphLine(gr, "1>2>4")
+	ReadDgraphLine(gr, "2>5")
+	ReadDgraphLine(gr, "1>3>6>7>8>9>10")
+	ReadDgraphLine(gr, "7>11")
+	l := NewLCA(gr, 1)
+	
+	c.Specify("Common ancestors", func() {
+		check := func(u, v, expected VertexId) {
+			lca, ok := l.Query(u, v)
+			c.Expect(ok, IsTrue)
+			c.Expect(lca, Equals, expected)
+		}
+		check(4, 5, 2)
+		check(5, 4, 2)
+		check(4, 10, 1)
+		check(10, 11, 7)
+		check(9, 6, 6)
+		check(3, 3, 3)
+		check(1, 8, 1)
+	})
+	
+	c.Specify("Same as naive ancestors search", func() {
+		ancestors := func(node VertexId) []VertexId {
+			res := []VertexId{node}
+			for node!=1 {
+				node = CollectVertexes(gr.GetPredecessors(node))[0]
+				res = append(res, node)
+			}
+			return res
+		}
+		for u := range gr.VertexesIter() {
+			for v := range gr.VertexesIter() {
+				vAncestors := make(map[VertexId]bool)
+				for _, node := range ancestors(v) {
+					vAncestors[node] = true
+				}
+				expected := VertexId(0)
+				for _, node := range ancestors(u) {
+					if vAncestors[node] {
+						expected = node
+						break
+					}
+				}
+				lca, _ := l.Query(u, v)
+				c.Expect(lca, Equals, expected)
+			}
+		}
+	})
+	
+	c.Specify("Vertex not in tree", func() {
+		_, ok := l.Query(4, 12)
+		c.Expect(ok, IsFalse)
+	})
+	
+	c.Specify("Not an arborescence", func() {
+		defer func() {
+			c.Expect(recover(), Not(IsNil))
+		}()
+		gr.AddArc(5, 6)
+		NewLCA(gr, 1)
+	})
+}
+
+func OfflineLCASpec(c gospec.Context) {
+	c.Specify("Same answers as LCAStructure on random trees", func() {
+		rng := rand.New(rand.NewSource(11))
+		for treeNum:=0; treeNum<5; treeNum++ {
+			gr := NewDirectedMap()
+			gr.AddNode(0)
+			for node:=1; node<50; node++ {
+				gr.AddArc(VertexId(rng.Intn(node)), VertexId(node))
+			}
+			queries := make([][2]VertexId, 100)
+			for i := range queries {
+				queries[i] = [2]VertexId{VertexId(rng.Intn(50)), VertexId(rng.Intn(50))}
+			}
+			queries = append(queries, [2]VertexId{7, 7}, [2]VertexId{0, 0})
+			l := NewLCA(gr, 0)
+			answers := OfflineLCA(gr, 0, queries)
+			c.Expect(len(answers), Equals, len(queries))
+			for i, query := range queries {
+				expected, _ := l.Query(query[0], query[1])
+				c.Expect(answers[i], Equals, expected)
+			}
+		}
+	})
+	
+	c.Specify("Vertex not in tree", func() {
+		defer func() {
+			c.Expect(recover(), Not(IsNil))
+		}()
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>3")
+		OfflineLCA(gr, 1, [][2]VertexId{[2]VertexId{2, 4}})
+	})
+}
 
 func TestAlgorithms(t *testing.T) {
 	r := gospec.NewRunner()
 	r.AddSpec(ReduceDirectPathsSpec)
 	r.AddSpec(TopologicalSortSpec)
+	r.AddSpec(LongestPathLayersSpec)
 	r.AddSpec(SplitGraphToIndependentSubgraphs_mixedSpec)
 	r.AddSpec(SplitGraphToIndependentSubgraphs_directedSpec)
 	r.AddSpec(SplitGraphToIndependentSubgraphs_undirectedSpec)
+	r.AddSpec(DominatorTreeSpec)
+	r.AddSpec(LCASpec)
+	r.AddSpec(OfflineLCASpec)
 	gospec.MainGoTest(r, t)
 }
@@ -0,0 +1,127 @@
+package graph
+
+import (
+	"sort"
+)
+
+// Greedy vertexes coloring of undirected graph.
+//
+// Vertexes are processed in given order (in increasing id order if order is
+// nil), and each vertex gets the smallest color (starting from 0), which isn't
+// used by it's already colored neighbours. Self-loops are ignored. Count of
+// distinct colors is an upper estimate of graph chromatic number.
+func GreedyColoring(gr UndirectedGraphReader, order []VertexId) map[VertexId]int {
+	if order==nil {
+		nodes := CollectVertexes(gr)
+		sort.Sort(Vertexes(nodes))
+		order = nodes
+	}
+	
+	colors := make(map[VertexId]int)
+	for _, node := range order {
+		usedColors := make(map[int]bool)
+		for neighbour := range gr.GetNeighbours(node).VertexesIter() {
+			if color, ok := colors[neighbour]; ok && neighbour!=node {
+				usedColors[color] = true
+			}
+		}
+		color := 0
+		for usedColors[color] {
+			color++
+		}
+		colors[node] = color
+	}
+	return colors
+}
+
+type vertexesByDegreeDesc struct {
+	nodes []VertexId
+	degrees map[VertexId]int
+}
+
+func (s *vertexesByDegreeDesc) Len() int {
+	return len(s.nodes)
+}
+
+func (s *vertexesByDegreeDesc) Less(i, j int) bool {
+	degree1, degree2 := s.degrees[s.nodes[i]], s.degrees[s.nodes[j]]
+	if degree1!=degree2 {
+		return degree1>degree2
+	}
+	return s.nodes[i]<s.nodes[j]
+}
+
+func (s *vertexesByDegreeDesc) Swap(i, j int) {
+	s.nodes[i], s.nodes[j] = s.nodes[j], s.nodes[i]
+}
+
+// Welsh-Powell vertexes order for GreedyColoring.
+//
+// Vertexes are sorted by decreasing degree, vertexes with equal degree are
+// sorted by id. Greedy coloring in this order usually uses less colors.
+func WelshPowellOrder(gr UndirectedGraphReader) []VertexId {
+	nodes := CollectVertexes(gr)
+	degrees := make(map[VertexId]int, len(nodes))
+	for _, node := range nodes {
+		degrees[node] = Degree(gr, node)
+	}
+	sort.Sort(&vertexesByDegreeDesc{nodes:nodes, degrees:degrees})
+	return nodes
+}
+
+// Approximate minimum vertex cover of undirected graph.
+//
+// Edges are processed in increasing (tail, head) order, and when edge isn't
+// covered yet both it's vertexes are added to cover. Chosen edges make a
+// matching, and any cover has at least one vertex of each matching edge, so
+// result is at most 2 times larger than minimum vertex cover.
+func ApproxVertexCover(gr UndirectedGraphEdgesReader) map[VertexId]bool {
+	edges := make([]Connection, 0, gr.EdgesCnt())
+	for conn := range gr.EdgesIter() {
+		edges = append(edges, conn)
+	}
+	sort.Sort(connectionsByNodes(edges))
+	
+	cover := make(map[VertexId]bool)
+	for _, conn := range edges {
+		if cover[conn.Tail] || cover[conn.Head] {
+			continue
+		}
+		cover[conn.Tail] = true
+		cover[conn.Head] = true
+	}
+	return cover
+}
+
+// Greedy maximal independent set of undirected graph.
+//
+// Vertexes are processed in given order (in increasing id order if order is
+// nil), and vertex is added to set if none of it's neighbours is already in
+// set. Vertex with self-loop is adjacent to itself, so it's never added.
+// Result is maximal: no vertex can be added without breaking independence,
+// but it isn't necessarily maximum.
+//
+// If order is nil, vertexes are taken from graph, when it's VertexesIterable,
+// and from edges otherwise.
+func GreedyMaximalIndependentSet(gr UndirectedGraphEdgesReader, order []VertexId) map[VertexId]bool {
+	if order==nil {
+		order = sortedUndirectedGraphVertexes(gr)
+	}
+	
+	set := make(map[VertexId]bool)
+	for _, node := range order {
+		if set[node] {
+			continue
+		}
+		independent := true
+		for neighbour := range gr.GetNeighbours(node).VertexesIter() {
+			if neighbour==node || set[neighbour] {
+				independent = false
+			}
+		}
+		if independent {
+			set[node] = true
+		}
+	}
+	return set
+}
@@ -0,0 +1,138 @@
+package graph
+
+import (
+	"testing"
+	"github.com/orfjackal/gospec/src/gospec"
+	. "github.com/orfjackal/gospec/src/gospec"
+)
+
+func colorsCount(colors map[VertexId]int) int {
+	distinct := make(map[int]bool)
+	for _, color := range colors {
+		distinct[color] = true
+	}
+	return len(distinct)
+}
+
+func GreedyColoringSpec(c gospec.Context) {
+	c.Specify("Coloring is proper", func() {
+		gr, _ := generateWeightedUndirectedGraph1()
+		colors := GreedyColoring(gr, nil)
+		c.Expect(len(colors), Equals, gr.Order())
+		for conn := range gr.EdgesIter() {
+			c.Expect(colors[conn.Tail], Not(Equals), colors[conn.Head])
+		}
+	})
+	
+	c.Specify("Vertexes order matters", func() {
+		// crown graph: 1,2,3 connected to 4,5,6 except 1-4, 2-5, 3-6
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-5-3-4-2-6-1")
+		c.Expect(colorsCount(GreedyColoring(gr, []VertexId{1, 4, 2, 5, 3, 6})), Equals, 3)
+		c.Expect(colorsCount(GreedyColoring(gr, []VertexId{1, 2, 3, 4, 5, 6})), Equals, 2)
+	})
+}
+
+func WelshPowellOrderSpec(c gospec.Context) {
+	gr := NewUndirectedMap()
+	ReadUgraphLine(gr, "1-2-3-1")
+	ReadUgraphLine(gr, "3-4")
+	ReadUgraphLine(gr, "3-5-2")
+	gr.AddNode(6)
+	c.Expect(WelshPowellOrder(gr), ContainsInOrder, Values(VertexId(3), VertexId(2), VertexId(1), VertexId(5), VertexId(4), VertexId(6)))
+	
+	colors := GreedyColoring(gr, WelshPowellOrder(gr))
+	c.Expect(colorsCount(colors), Equals, 3)
+}
+
+func ApproxVertexCoverSpec(c gospec.Context) {
+	c.Specify("Every edge is covered", func() {
+		gr, _ := generateWeightedUndirectedGraph1()
+		cover := ApproxVertexCover(gr)
+		for conn := range gr.EdgesIter() {
+			c.Expect(cover[conn.Tail] || cover[conn.Head], IsTrue)
+		}
+	})
+	
+	c.Specify("Grid is covered", func() {
+		gr := GridGraph(3, 4)
+		cover := ApproxVertexCover(gr)
+		for conn := range gr.EdgesIter() {
+			c.Expect(cover[conn.Tail] || cover[conn.Head], IsTrue)
+		}
+	})
+	
+	c.Specify("Cover is at most 2 times larger than minimum", func() {
+		// minimum cover of path with 7 vertexes is 2, 4, 6
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-4-5-6-7")
+		c.Expect(len(ApproxVertexCover(gr)) <= 2*3, IsTrue)
+	})
+	
+	c.Specify("Star is covered by edge", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "2-1-3")
+		ReadUgraphLine(gr, "1-4")
+		cover := ApproxVertexCover(gr)
+		c.Expect(len(cover), Equals, 2)
+		c.Expect(cover[1], IsTrue)
+	})
+	
+	c.Specify("Graph without edges", func() {
+		gr := NewUndirectedMap()
+		gr.AddNode(1)
+		c.Expect(len(ApproxVertexCover(gr)), Equals, 0)
+	})
+}
+
+func GreedyMaximalIndependentSetSpec(c gospec.Context) {
+	c.Specify("Set is independent and maximal", func() {
+		gr, _ := generateWeightedUndirectedGraph1()
+		set := GreedyMaximalIndependentSet(gr, nil)
+		for conn := range gr.EdgesIter() {
+			c.Expect(set[conn.Tail] && set[conn.Head], IsFalse)
+		}
+		for node := range gr.VertexesIter() {
+			if set[node] {
+				continue
+			}
+			// each vertex out of set has neighbour in set
+			hasNeighbourInSet := false
+			for neighbour := range gr.GetNeighbours(node).VertexesIter() {
+				if set[neighbour] {
+					hasNeighbourInSet = true
+				}
+			}
+			c.Expect(hasNeighbourInSet, IsTrue)
+		}
+	})
+	
+	c.Specify("Vertexes order matters", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "2-1-3")
+		ReadUgraphLine(gr, "1-4")
+		c.Expect(len(GreedyMaximalIndependentSet(gr, nil)), Equals, 1)
+		c.Expect(len(GreedyMaximalIndependentSet(gr, []VertexId{2, 1, 3, 4})), Equals, 3)
+	})
+	
+	c.Specify("Isolated vertex is in set and self-loop vertex isn't", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2")
+		gr.AddEdge(3, 3)
+		gr.AddNode(4)
+		set := GreedyMaximalIndependentSet(gr, nil)
+		c.Expect(len(set), Equals, 2)
+		c.Expect(set[1], IsTrue)
+		c.Expect(set[3], IsFalse)
+		c.Expect(set[4], IsTrue)
+	})
+}
+
+func TestColoring(t *testing.T) {
+	r := gospec.NewRunner()
+	r.AddSpec(GreedyColoringSpec)
+	r.AddSpec(WelshPowellOrderSpec)
+	r.AddSpec(ApproxVertexCoverSpec)
+	r.AddSpec(GreedyMaximalIndependentSetSpec)
+	gospec.MainGoTest(r, t)
+}
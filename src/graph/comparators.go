@@ -1,6 +1,8 @@
 package graph
 
 import (
+	"sort"
+
 	"github.com/StepLg/go-erx/src/erx"
 )
 
@@ -145,6 +147,83 @@ func UndirectedGraphsEquals(gr1, gr2 UndirectedGraphReader) bool {
 	return true
 }
 
+// Check if two directed graphs have the same vertexes and arcs
+//
+// Unlike DirectedGraphsEquals, vertexes and arcs of both graphs are read to
+// sets with VertexesIter and ArcsIter, and then sets are compared, so result
+// doesn't depend on iteration order and iterators are never left blocked.
+// Graphs are compared by vertexes ids, it isn't isomorphism check.
+func Equal(gr1, gr2 DirectedGraphReader) bool {
+	if !vertexesSetsEqual(gr1, gr2) {
+		return false
+	}
+	return connectionsSetsEqual(collectConnectionsSet(gr1.ArcsIter(), false), collectConnectionsSet(gr2.ArcsIter(), false))
+}
+
+// Check if two undirected graphs have the same vertexes and edges
+//
+// Same as Equal, but edges a-b and b-a are the same.
+func EqualUndirected(gr1, gr2 UndirectedGraphReader) bool {
+	if !vertexesSetsEqual(gr1, gr2) {
+		return false
+	}
+	return connectionsSetsEqual(collectConnectionsSet(gr1.EdgesIter(), true), collectConnectionsSet(gr2.EdgesIter(), true))
+}
+
+func vertexesSetsEqual(nodes1, nodes2 VertexesIterable) bool {
+	set1 := make(map[VertexId]bool)
+	for node := range nodes1.VertexesIter() {
+		set1[node] = true
+	}
+	set2 := make(map[VertexId]bool, len(set1))
+	for node := range nodes2.VertexesIter() {
+		set2[node] = true
+	}
+	if len(set1)!=len(set2) {
+		return false
+	}
+	for node := range set1 {
+		if !set2[node] {
+			return false
+		}
+	}
+	return true
+}
+
+// Read all connections from channel to set: set[tail][head]. If undirected
+// flag is set, then tail is always not greater than head.
+func collectConnectionsSet(connections <-chan Connection, undirected bool) map[VertexId]map[VertexId]bool {
+	set := make(map[VertexId]map[VertexId]bool)
+	for conn := range connections {
+		tail, head := conn.Tail, conn.Head
+		if undirected && tail>head {
+			tail, head = head, tail
+		}
+		if _, ok := set[tail]; !ok {
+			set[tail] = make(map[VertexId]bool)
+		}
+		set[tail][head] = true
+	}
+	return set
+}
+
+func connectionsSetsEqual(set1, set2 map[VertexId]map[VertexId]bool) bool {
+	if len(set1)!=len(set2) {
+		return false
+	}
+	for tail, heads := range set1 {
+		if len(heads)!=len(set2[tail]) {
+			return false
+		}
+		for head := range heads {
+			if !set2[tail][head] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // Interface for ContainPath function.
 type NodeAndConnectionChecker interface {
 	// Check if node exist in graph.
@@ -277,3 +356,181 @@ func ContainDirectedPath(gr DirectedGraphReader, path []VertexId, unexistNodePan
 func ContainMixedPath(gr MixedGraphReader, path []VertexId, unexistNodePanic bool) bool {
 	return ContainPath(NodeAndConnectionChecker(&mixedNodeAndConnectionChecker{gr:gr}), path, unexistNodePanic)
 }
+
+// Vertex degrees in directed graph, used to prune isomorphism search.
+type vertexDegrees struct {
+	in, out int
+	selfLoop bool
+}
+
+// Adjacency and degrees of directed graph for isomorphism search.
+type isomorphismGraphInfo struct {
+	nodes []VertexId
+	arcs map[VertexId]map[VertexId]bool
+	degrees map[VertexId]vertexDegrees
+}
+
+func newIsomorphismGraphInfo(gr DirectedGraphReader) *isomorphismGraphInfo {
+	info := &isomorphismGraphInfo{
+		nodes: SortedVertexes(gr),
+		arcs: make(map[VertexId]map[VertexId]bool),
+		degrees: make(map[VertexId]vertexDegrees),
+	}
+	for _, node := range info.nodes {
+		info.arcs[node] = make(map[VertexId]bool)
+	}
+	for conn := range gr.ArcsIter() {
+		info.arcs[conn.Tail][conn.Head] = true
+	}
+	for tail, heads := range info.arcs {
+		for head := range heads {
+			tailDegrees, headDegrees := info.degrees[tail], info.degrees[head]
+			tailDegrees.out++
+			headDegrees.in++
+			if tail==head {
+				headDegrees.out++
+				headDegrees.selfLoop = true
+				info.degrees[head] = headDegrees
+			} else {
+				info.degrees[tail] = tailDegrees
+				info.degrees[head] = headDegrees
+			}
+		}
+	}
+	return info
+}
+
+// Sorted degrees of all vertexes, encoded to single int sequence.
+func (info *isomorphismGraphInfo) degreesSequence() []int {
+	seq := make([]int, 0, len(info.nodes))
+	for _, node := range info.nodes {
+		d := info.degrees[node]
+		code := 2*(d.in*(len(info.nodes)+1) + d.out)
+		if d.selfLoop {
+			code++
+		}
+		seq = append(seq, code)
+	}
+	sort.Ints(seq)
+	return seq
+}
+
+// Backtracking state of isomorphism search from graph g1 to graph g2.
+type isomorphismState struct {
+	g1, g2 *isomorphismGraphInfo
+	// g1 vertexes in order of matching
+	order []VertexId
+	mapping map[VertexId]VertexId
+	used map[VertexId]bool
+}
+
+// Vertexes order for matching: each next vertex has maximal count of arcs to
+// already ordered vertexes, so inconsistent mapping is found as early as
+// possible. Ties are broken by larger degree and then by smaller id.
+func (s *isomorphismState) initOrder() {
+	s.order = make([]VertexId, 0, len(s.g1.nodes))
+	ordered := make(map[VertexId]bool)
+	links := make(map[VertexId]int)
+	for len(s.order)<len(s.g1.nodes) {
+		bestNode := VertexId(0)
+		found := false
+		for _, node := range s.g1.nodes {
+			if ordered[node] {
+				continue
+			}
+			if !found || links[node]>links[bestNode] ||
+				(links[node]==links[bestNode] && s.degree(node)>s.degree(bestNode)) {
+				bestNode = node
+				found = true
+			}
+		}
+		ordered[bestNode] = true
+		s.order = append(s.order, bestNode)
+		for _, node := range s.g1.nodes {
+			if node!=bestNode && (s.g1.arcs[bestNode][node] || s.g1.arcs[node][bestNode]) {
+				links[node]++
+			}
+		}
+	}
+}
+
+func (s *isomorphismState) degree(node VertexId) int {
+	d := s.g1.degrees[node]
+	return d.in + d.out
+}
+
+// Check if g1 vertex node can be mapped to g2 vertex candidate, consistently
+// with already mapped vertexes.
+func (s *isomorphismState) isFeasible(node, candidate VertexId) bool {
+	d1, d2 := s.g1.degrees[node], s.g2.degrees[candidate]
+	if d1.in!=d2.in || d1.out!=d2.out || d1.selfLoop!=d2.selfLoop {
+		return false
+	}
+	for mappedNode, mappedCandidate := range s.mapping {
+		if s.g1.arcs[node][mappedNode]!=s.g2.arcs[candidate][mappedCandidate] ||
+			s.g1.arcs[mappedNode][node]!=s.g2.arcs[mappedCandidate][candidate] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *isomorphismState) match(pos int) bool {
+	if pos==len(s.order) {
+		return true
+	}
+	node := s.order[pos]
+	for _, candidate := range s.g2.nodes {
+		if s.used[candidate] || !s.isFeasible(node, candidate) {
+			continue
+		}
+		s.mapping[node] = candidate
+		s.used[candidate] = true
+		if s.match(pos+1) {
+			return true
+		}
+		s.mapping[node] = 0, false
+		s.used[candidate] = false, false
+	}
+	return false
+}
+
+// Check if two directed graphs are isomorphic
+//
+// Graphs are isomorphic if there is one-to-one vertexes mapping from gr1 to
+// gr2, which keeps all arcs (including self-loops). Graphs with different
+// vertexes, arcs counts or degrees sequences are rejected immediately.
+// Otherwise VF2-like backtracking maps vertexes one by one, checking degrees
+// and arcs to already mapped vertexes on each step.
+//
+// Search takes exponential time in the worst case, so it's intended for small
+// graphs, up to a few dozens vertexes.
+//
+// Returns mapping from gr1 vertexes to gr2 vertexes if graphs are isomorphic,
+// and (false, nil) otherwise.
+func AreIsomorphic(gr1, gr2 DirectedGraphReader) (bool, map[VertexId]VertexId) {
+	if gr1.Order()!=gr2.Order() || gr1.ArcsCnt()!=gr2.ArcsCnt() {
+		return false, nil
+	}
+	
+	g1 := newIsomorphismGraphInfo(gr1)
+	g2 := newIsomorphismGraphInfo(gr2)
+	seq1, seq2 := g1.degreesSequence(), g2.degreesSequence()
+	for i := range seq1 {
+		if seq1[i]!=seq2[i] {
+			return false, nil
+		}
+	}
+	
+	s := &isomorphismState{
+		g1: g1,
+		g2: g2,
+		mapping: make(map[VertexId]VertexId),
+		used: make(map[VertexId]bool),
+	}
+	s.initOrder()
+	if !s.match(0) {
+		return false, nil
+	}
+	return true, s.mapping
+}
@@ -82,8 +82,142 @@ func ComparatorsSpec(c gospec.Context) {
 	})
 }
 
+func EqualSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	
+	c.Specify("Graph is equal to it's copy", func() {
+		grcopy := NewDirectedMap()
+		CopyDirectedGraph(gr, grcopy)
+		c.Expect(Equal(gr, grcopy), IsTrue)
+		c.Expect(Equal(grcopy, gr), IsTrue)
+	})
+	
+	c.Specify("Graphs of different types", func() {
+		grcopy := NewMixedMatrix(10)
+		CopyDirectedGraph(gr, grcopy)
+		c.Expect(Equal(gr, grcopy), IsTrue)
+	})
+	
+	c.Specify("Reversed arc", func() {
+		grcopy := NewDirectedMap()
+		CopyDirectedGraph(gr, grcopy)
+		grcopy.RemoveArc(4, 5)
+		grcopy.AddArc(5, 4)
+		c.Expect(Equal(gr, grcopy), IsFalse)
+		c.Expect(Equal(grcopy, gr), IsFalse)
+	})
+	
+	c.Specify("Isolated vertex", func() {
+		grcopy := NewDirectedMap()
+		CopyDirectedGraph(gr, grcopy)
+		grcopy.AddNode(7)
+		c.Expect(Equal(gr, grcopy), IsFalse)
+		c.Expect(Equal(grcopy, gr), IsFalse)
+		gr.AddNode(7)
+		c.Expect(Equal(gr, grcopy), IsTrue)
+	})
+	
+	c.Specify("Undirected graphs", func() {
+		ugr1 := NewUndirectedMap()
+		ReadUgraphLine(ugr1, "1-2-3-1")
+		ugr2 := NewUndirectedMatrix(10)
+		ReadUgraphLine(ugr2, "3-2-1-3")
+		c.Expect(EqualUndirected(ugr1, ugr2), IsTrue)
+		ugr2.AddNode(4)
+		c.Expect(EqualUndirected(ugr1, ugr2), IsFalse)
+		ugr1.AddEdge(4, 1)
+		ugr2.AddEdge(4, 2)
+		c.Expect(EqualUndirected(ugr1, ugr2), IsFalse)
+	})
+}
+
+// Check that mapping is one-to-one and keeps all arcs.
+func isIsomorphismMapping(gr1, gr2 DirectedGraphReader, mapping map[VertexId]VertexId) bool {
+	if len(mapping)!=gr1.Order() {
+		return false
+	}
+	images := make(map[VertexId]bool)
+	for _, image := range mapping {
+		images[image] = true
+	}
+	if len(images)!=gr2.Order() {
+		return false
+	}
+	res := true
+	for conn := range gr1.ArcsIter() {
+		if !gr2.CheckArc(mapping[conn.Tail], mapping[conn.Head]) {
+			res = false
+		}
+	}
+	return res
+}
+
+func AreIsomorphicSpec(c gospec.Context) {
+	c.Specify("Relabeled graph", func() {
+		gr1 := generateDirectedGraph1()
+		gr2 := NewDirectedMap()
+		// vertex i of gr1 is vertex 10*i of gr2
+		for conn := range gr1.ArcsIter() {
+			gr2.AddArc(10*conn.Tail, 10*conn.Head)
+		}
+		ok, mapping := AreIsomorphic(gr1, gr2)
+		c.Expect(ok, IsTrue)
+		c.Expect(isIsomorphismMapping(gr1, gr2, mapping), IsTrue)
+	})
+	
+	c.Specify("Cycle with reversed labels", func() {
+		gr1 := NewDirectedMap()
+		ReadDgraphLine(gr1, "1>2>3>4>5>1")
+		gr1.AddArc(3, 3)
+		gr2 := NewDirectedMap()
+		ReadDgraphLine(gr2, "5>4>3>2>1>5")
+		gr2.AddArc(1, 1)
+		ok, mapping := AreIsomorphic(gr1, gr2)
+		c.Expect(ok, IsTrue)
+		c.Expect(isIsomorphismMapping(gr1, gr2, mapping), IsTrue)
+		c.Expect(mapping[3], Equals, VertexId(1))
+	})
+	
+	c.Specify("Different counts are rejected", func() {
+		gr1 := generateDirectedGraph1()
+		gr2 := generateDirectedGraph1()
+		gr2.AddNode(7)
+		ok, mapping := AreIsomorphic(gr1, gr2)
+		c.Expect(ok, IsFalse)
+		c.Expect(mapping, IsNil)
+		gr1.AddArc(5, 6)
+		ok, _ = AreIsomorphic(gr1, gr1)
+		c.Expect(ok, IsTrue)
+		ok, _ = AreIsomorphic(gr1, generateDirectedGraph1())
+		c.Expect(ok, IsFalse)
+	})
+	
+	c.Specify("Same degrees, but different structure", func() {
+		// two directed triangles and directed cycle of six vertexes
+		gr1 := NewDirectedMap()
+		ReadDgraphLine(gr1, "1>2>3>1")
+		ReadDgraphLine(gr1, "4>5>6>4")
+		gr2 := NewDirectedMap()
+		ReadDgraphLine(gr2, "1>2>3>4>5>6>1")
+		ok, _ := AreIsomorphic(gr1, gr2)
+		c.Expect(ok, IsFalse)
+	})
+	
+	c.Specify("Arc direction matters", func() {
+		gr1 := NewDirectedMap()
+		ReadDgraphLine(gr1, "1>2>3")
+		ReadDgraphLine(gr1, "1>3")
+		gr2 := NewDirectedMap()
+		ReadDgraphLine(gr2, "1>2>3>1")
+		ok, _ := AreIsomorphic(gr1, gr2)
+		c.Expect(ok, IsFalse)
+	})
+}
+
 func TestComparators(t *testing.T) {
 	r := gospec.NewRunner()
 	r.AddSpec(ComparatorsSpec)
+	r.AddSpec(EqualSpec)
+	r.AddSpec(AreIsomorphicSpec)
 	gospec.MainGoTest(r, t)
 }
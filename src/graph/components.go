@@ -0,0 +1,501 @@
+package graph
+
+import (
+	"sort"
+)
+
+// Strongly connected components of directed graph.
+//
+// Tarjan's algorithm with explicit stack instead of recursion, so it works
+// fine on very deep graphs. Each component is a slice of vertexes, and every
+// vertex of graph belongs to exactly one component (single vertex without
+// cycles through it is a component too).
+//
+// Components are returned in reverse topological order: there is no arc from
+// any component to components after it. Vertexes and their accessors are
+// processed in increasing id order, so result is the same for equal graphs.
+func StronglyConnectedComponents(gr DirectedGraphReader) [][]VertexId {
+	neighboursExtractor := NewDgraphOutNeighboursExtractor(gr)
+	components := make([][]VertexId, 0, 10)
+	
+	// discovery index and lowlink of each visited node
+	index := make(map[VertexId]int)
+	lowlink := make(map[VertexId]int)
+	nextIndex := 0
+	// Tarjan's nodes stack and nodes, which are currently in it
+	nodesStack := make([]VertexId, 0, 10)
+	onStack := make(map[VertexId]bool)
+	// depth-first search stack
+	stack := make([]dfsFrame, 0, 10)
+	
+	visit := func(node VertexId) {
+		index[node] = nextIndex
+		lowlink[node] = nextIndex
+		nextIndex++
+		nodesStack = append(nodesStack, node)
+		onStack[node] = true
+		stack = append(stack, dfsFrame{
+			node: node,
+			neighbours: SortedVertexes(neighboursExtractor.GetOutNeighbours(node)),
+		})
+	}
+	
+	for _, startNode := range SortedVertexes(gr) {
+		if _, ok := index[startNode]; ok {
+			continue
+		}
+		visit(startNode)
+		for len(stack)>0 {
+			top := &stack[len(stack)-1]
+			if top.pos<len(top.neighbours) {
+				nextNode := top.neighbours[top.pos]
+				top.pos++
+				if _, ok := index[nextNode]; !ok {
+					visit(nextNode)
+				} else if onStack[nextNode] && index[nextNode]<lowlink[top.node] {
+					lowlink[top.node] = index[nextNode]
+				}
+				continue
+			}
+			
+			// all accessors are processed
+			node := top.node
+			stack = stack[0:len(stack)-1]
+			if lowlink[node]==index[node] {
+				// node is a root of component
+				pos := len(nodesStack)-1
+				for nodesStack[pos]!=node {
+					pos--
+				}
+				component := make([]VertexId, len(nodesStack)-pos)
+				copy(component, nodesStack[pos:])
+				for _, componentNode := range component {
+					onStack[componentNode] = false, false
+				}
+				nodesStack = nodesStack[0:pos]
+				components = append(components, component)
+			}
+			if len(stack)>0 {
+				parent := stack[len(stack)-1].node
+				if lowlink[node]<lowlink[parent] {
+					lowlink[parent] = lowlink[node]
+				}
+			}
+		}
+	}
+	
+	return components
+}
+
+// Condensation of directed graph: each strongly connected component is
+// contracted to a single vertex.
+//
+// Components get new ids from 0 to components count - 1 in topological order,
+// so every arc goes from smaller id to bigger one. There is an arc between two
+// components, if there is any arc between their vertexes in original graph.
+// Condensation graph is always acyclic.
+//
+// Returns condensation graph and map from each original vertex to id of it's
+// component.
+func Condensation(gr DirectedGraphReader) (DirectedGraph, map[VertexId]VertexId) {
+	components := StronglyConnectedComponents(gr)
+	componentOf := make(map[VertexId]VertexId)
+	res := NewDirectedMap()
+	for i, component := range components {
+		// components are in reverse topological order
+		componentId := VertexId(len(components) - 1 - i)
+		res.AddNode(componentId)
+		for _, node := range component {
+			componentOf[node] = componentId
+		}
+	}
+	for conn := range gr.ArcsIter() {
+		tail, head := componentOf[conn.Tail], componentOf[conn.Head]
+		if tail!=head && !res.CheckArc(tail, head) {
+			res.AddArc(tail, head)
+		}
+	}
+	return res, componentOf
+}
+
+// Components slice, sorted by smallest component vertex.
+//
+// Each component must be already sorted.
+type componentsBySmallest [][]VertexId
+
+func (c componentsBySmallest) Less(i, j int) bool {
+	return c[i][0] < c[j][0]
+}
+
+func (c componentsBySmallest) Swap(i, j int) {
+	c[i], c[j] = c[j], c[i]
+}
+
+func (c componentsBySmallest) Len() int {
+	return len(c)
+}
+
+// Group vertexes to components by representative vertex of their set.
+//
+// Vertexes in each component are sorted, and components are sorted by
+// their smallest vertex.
+func componentsFromDisjointSets(nodes []VertexId, sets *vertexesDisjointSets) [][]VertexId {
+	// component index for each set representative
+	componentIndex := make(map[VertexId]int)
+	components := make([][]VertexId, 0, 10)
+	for _, node := range nodes {
+		root := sets.Find(node)
+		index, ok := componentIndex[root]
+		if !ok {
+			index = len(components)
+			componentIndex[root] = index
+			components = append(components, make([]VertexId, 0, 1))
+		}
+		components[index] = append(components[index], node)
+	}
+	
+	for _, component := range components {
+		sort.Sort(Vertexes(component))
+	}
+	sort.Sort(componentsBySmallest(components))
+	return components
+}
+
+// Weakly connected components of directed graph.
+//
+// Two vertexes are in the same component if there is a path between them,
+// ignoring arcs direction. Every vertex of graph belongs to exactly one
+// component, isolated vertexes are single element components.
+//
+// Vertexes in each component are sorted, and components are sorted by their
+// smallest vertex.
+func WeaklyConnectedComponents(gr DirectedGraphReader) [][]VertexId {
+	sets := newVertexesDisjointSets()
+	for conn := range gr.ArcsIter() {
+		sets.Union(conn.Tail, conn.Head)
+	}
+	return componentsFromDisjointSets(CollectVertexes(gr), sets)
+}
+
+// Connected components of undirected graph.
+//
+// Each component is filled with breadth-first search over GetNeighbours. Every
+// vertex of graph belongs to exactly one component, isolated vertexes are
+// single element components.
+//
+// Vertexes in each component are sorted, and components are sorted by their
+// smallest vertex.
+func ConnectedComponents(gr UndirectedGraphReader) [][]VertexId {
+	neighboursExtractor := NewUgraphOutNeighboursExtractor(gr)
+	visited := make(map[VertexId]bool)
+	components := make([][]VertexId, 0, 10)
+	for _, startNode := range CollectVertexes(gr) {
+		if _, ok := visited[startNode]; ok {
+			continue
+		}
+		component := make([]VertexId, 0, 1)
+		BreadthFirstVisit(neighboursExtractor, startNode, func(node VertexId) {
+			visited[node] = true
+			component = append(component, node)
+		})
+		sort.Sort(Vertexes(component))
+		components = append(components, component)
+	}
+	sort.Sort(componentsBySmallest(components))
+	return components
+}
+
+// Articulation points (cut vertexes) of undirected graph.
+//
+// Articulation point is a vertex, whose removal increases number of connected
+// components. Non-root vertex of depth-first search tree is an articulation
+// point if some of it's children can't reach vertexes above it without it, and
+// root is an articulation point if it has at least two children. See
+// undirectedLowLinks.
+//
+// Vertexes are returned in increasing id order.
+func ArticulationPoints(gr UndirectedGraphEdgesReader) []VertexId {
+	isArticulation, _ := undirectedLowLinks(gr)
+	points := make(Vertexes, 0, len(isArticulation))
+	for node, _ := range isArticulation {
+		points = append(points, node)
+	}
+	sort.Sort(points)
+	return points
+}
+
+// Bridges (cut edges) of undirected graph.
+//
+// Bridge is an edge, whose removal increases number of connected components:
+// edge to child of depth-first search tree, which can't reach parent or
+// vertexes above it without this edge. See undirectedLowLinks. Parallel edges
+// are never bridges, if graph reader allows them.
+//
+// Each bridge is returned with Tail<=Head, and bridges are sorted by tail and
+// then by head.
+func Bridges(gr UndirectedGraphEdgesReader) []Connection {
+	_, bridges := undirectedLowLinks(gr)
+	sort.Sort(connectionsByNodes(bridges))
+	return bridges
+}
+
+// 2-edge-connected components of undirected graph.
+//
+// Two vertexes are in the same component if they stay connected after removal
+// of any single edge, i.e. there is no bridge between them. So components are
+// connected components of graph without bridges. Every vertex belongs to
+// exactly one component, isolated vertexes are single element components.
+//
+// Vertexes are taken from graph, when it's VertexesIterable, and from edges
+// otherwise. Vertexes in each component are sorted, and components are sorted
+// by their smallest vertex.
+func TwoEdgeConnectedComponents(gr UndirectedGraphEdgesReader) [][]VertexId {
+	return componentsFromDisjointSets(sortedUndirectedGraphVertexes(gr), twoEdgeConnectedSets(gr))
+}
+
+// Check if two vertexes are in the same 2-edge-connected component.
+//
+// See TwoEdgeConnectedComponents. Vertex is 2-edge-connected with itself, and
+// if any of vertexes isn't in graph, then false is returned.
+func Are2EdgeConnected(gr UndirectedGraphEdgesReader, u, v VertexId) bool {
+	nodes := sortedUndirectedGraphVertexes(gr)
+	hasU, hasV := false, false
+	for _, node := range nodes {
+		hasU = hasU || node==u
+		hasV = hasV || node==v
+	}
+	if !hasU || !hasV {
+		return false
+	}
+	sets := twoEdgeConnectedSets(gr)
+	return sets.Find(u)==sets.Find(v)
+}
+
+// Disjoint sets of vertexes, connected with non-bridge edges.
+func twoEdgeConnectedSets(gr UndirectedGraphEdgesReader) *vertexesDisjointSets {
+	_, bridges := undirectedLowLinks(gr)
+	isBridge := make(map[VertexId]map[VertexId]bool)
+	for _, bridge := range bridges {
+		if _, ok := isBridge[bridge.Tail]; !ok {
+			isBridge[bridge.Tail] = make(map[VertexId]bool)
+		}
+		isBridge[bridge.Tail][bridge.Head] = true
+	}
+	sets := newVertexesDisjointSets()
+	for conn := range gr.EdgesIter() {
+		edge := NewUndirectedConnection(conn.Tail, conn.Head).Connection
+		if !isBridge[edge.Tail][edge.Head] {
+			sets.Union(edge.Tail, edge.Head)
+		}
+	}
+	return sets
+}
+
+// Iterative depth-first search with discovery times and low links.
+//
+// Low link of vertex is the minimal discovery time of vertexes, reachable from
+// vertex subtree with at most one back edge. Edge to parent is identified by
+// id, not by parent vertex, so parallel edges work as back edges.
+//
+// Returns set of articulation points and list of bridges.
+func undirectedLowLinks(gr UndirectedGraphEdgesReader) (map[VertexId]bool, []Connection) {
+	adjacent, _ := undirectedEdgesAdjacency(gr)
+	nodes := make(Vertexes, 0, len(adjacent))
+	for node, _ := range adjacent {
+		nodes = append(nodes, node)
+	}
+	sort.Sort(nodes)
+	
+	type lowLinkFrame struct {
+		node VertexId
+		parentEdge int // edge from parent, -1 for root
+		pos int // position of next adjacent edge to process
+		children int
+	}
+	
+	// discovery time and low link of each visited node
+	disc := make(map[VertexId]int)
+	low := make(map[VertexId]int)
+	time := 0
+	isArticulation := make(map[VertexId]bool)
+	bridges := make([]Connection, 0, 10)
+	stack := make([]lowLinkFrame, 0, 10)
+	
+	for _, root := range nodes {
+		if _, ok := disc[root]; ok {
+			continue
+		}
+		disc[root] = time
+		low[root] = time
+		time++
+		stack = append(stack, lowLinkFrame{node:root, parentEdge:-1})
+		for len(stack)>0 {
+			top := &stack[len(stack)-1]
+			if edges := adjacent[top.node]; top.pos<len(edges) {
+				edge := edges[top.pos]
+				top.pos++
+				if edge.edgeId==top.parentEdge {
+					continue
+				}
+				if nextDisc, ok := disc[edge.node]; ok {
+					if nextDisc<low[top.node] {
+						low[top.node] = nextDisc
+					}
+				} else {
+					disc[edge.node] = time
+					low[edge.node] = time
+					time++
+					top.children++
+					stack = append(stack, lowLinkFrame{node:edge.node, parentEdge:edge.edgeId})
+				}
+				continue
+			}
+			
+			// all adjacent edges are processed
+			node := top.node
+			children := top.children
+			stack = stack[0:len(stack)-1]
+			if len(stack)==0 {
+				if children>=2 {
+					isArticulation[node] = true
+				}
+				continue
+			}
+			parent := stack[len(stack)-1].node
+			if low[node]<low[parent] {
+				low[parent] = low[node]
+			}
+			if len(stack)>1 && low[node]>=disc[parent] {
+				isArticulation[parent] = true
+			}
+			if low[node]>disc[parent] {
+				bridges = append(bridges, NewUndirectedConnection(parent, node).Connection)
+			}
+		}
+	}
+	
+	return isArticulation, bridges
+}
+
+// Edges components slice, sorted by smallest component edge.
+//
+// Each component must be already sorted by tail and then by head.
+type edgesComponentsBySmallest [][]Connection
+
+func (c edgesComponentsBySmallest) Less(i, j int) bool {
+	if c[i][0].Tail!=c[j][0].Tail {
+		return c[i][0].Tail<c[j][0].Tail
+	}
+	return c[i][0].Head<c[j][0].Head
+}
+
+func (c edgesComponentsBySmallest) Swap(i, j int) {
+	c[i], c[j] = c[j], c[i]
+}
+
+func (c edgesComponentsBySmallest) Len() int {
+	return len(c)
+}
+
+// Biconnected (2-vertex-connected) components of undirected graph.
+//
+// Each edge belongs to exactly one component, and two edges are in the same
+// component if they lie on a common simple cycle. So bridge is a component of
+// single edge, and articulation points are shared by several components.
+// Components are collected with edge stack in the same iterative depth-first
+// search as in undirectedLowLinks: when child subtree can't reach vertexes
+// above parent, edges from stack till the edge to child form a component.
+// Parallel edges are in the same component, self-loops are ignored.
+//
+// Each edge is returned with Tail<=Head, edges in each component are sorted by
+// tail and then by head, and components are sorted by their smallest edge.
+func BiconnectedComponents(gr UndirectedGraphEdgesReader) [][]Connection {
+	adjacent, _ := undirectedEdgesAdjacency(gr)
+	nodes := make(Vertexes, 0, len(adjacent))
+	for node, _ := range adjacent {
+		nodes = append(nodes, node)
+	}
+	sort.Sort(nodes)
+	
+	type biconnectedFrame struct {
+		node VertexId
+		parentEdge int // edge from parent, -1 for root
+		pos int // position of next adjacent edge to process
+	}
+	type stackEdge struct {
+		conn Connection
+		edgeId int
+	}
+	
+	disc := make(map[VertexId]int)
+	low := make(map[VertexId]int)
+	time := 0
+	edgesStack := make([]stackEdge, 0, 10)
+	components := make([][]Connection, 0, 10)
+	stack := make([]biconnectedFrame, 0, 10)
+	
+	for _, root := range nodes {
+		if _, ok := disc[root]; ok {
+			continue
+		}
+		disc[root] = time
+		low[root] = time
+		time++
+		stack = append(stack, biconnectedFrame{node:root, parentEdge:-1})
+		for len(stack)>0 {
+			top := &stack[len(stack)-1]
+			if edges := adjacent[top.node]; top.pos<len(edges) {
+				edge := edges[top.pos]
+				top.pos++
+				if edge.edgeId==top.parentEdge || edge.node==top.node {
+					continue
+				}
+				nextDisc, ok := disc[edge.node]
+				if ok && nextDisc>disc[top.node] {
+					// back edge, which is already on stack from descendant side
+					continue
+				}
+				edgesStack = append(edgesStack, stackEdge{NewUndirectedConnection(top.node, edge.node).Connection, edge.edgeId})
+				if ok {
+					if nextDisc<low[top.node] {
+						low[top.node] = nextDisc
+					}
+				} else {
+					disc[edge.node] = time
+					low[edge.node] = time
+					time++
+					stack = append(stack, biconnectedFrame{node:edge.node, parentEdge:edge.edgeId})
+				}
+				continue
+			}
+			
+			// all adjacent edges are processed
+			node := top.node
+			parentEdge := top.parentEdge
+			stack = stack[0:len(stack)-1]
+			if len(stack)==0 {
+				continue
+			}
+			parent := stack[len(stack)-1].node
+			if low[node]<low[parent] {
+				low[parent] = low[node]
+			}
+			if low[node]>=disc[parent] {
+				component := make([]Connection, 0, 1)
+				for {
+					last := edgesStack[len(edgesStack)-1]
+					edgesStack = edgesStack[0:len(edgesStack)-1]
+					component = append(component, last.conn)
+					if last.edgeId==parentEdge {
+						break
+					}
+				}
+				sort.Sort(connectionsByNodes(component))
+				components = append(components, component)
+			}
+		}
+	}
+	
+	sort.Sort(edgesComponentsBySmallest(components))
+	return components
+}
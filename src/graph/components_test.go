@@ -0,0 +1,341 @@
+package graph
+
+import (
+	"testing"
+	"github.com/orfjackal/gospec/src/gospec"
+	. "github.com/orfjackal/gospec/src/gospec"
+)
+
+func StronglyConnectedComponentsSpec(c gospec.Context) {
+	c.Specify("Acyclic graph has only single node components", func() {
+		gr := generateDirectedGraph1()
+		components := StronglyConnectedComponents(gr)
+		c.Expect(len(components), Equals, gr.Order())
+		for _, component := range components {
+			c.Expect(len(component), Equals, 1)
+		}
+	})
+	
+	c.Specify("Components order is reproducible", func() {
+		gr := generateDirectedGraph1()
+		for i:=0; i<10; i++ {
+			components := StronglyConnectedComponents(gr)
+			c.Expect(len(components), Equals, 6)
+			order := make([]VertexId, len(components))
+			for j, component := range components {
+				order[j] = component[0]
+			}
+			c.Expect(order, ContainsInOrder, Values(VertexId(5), VertexId(4), VertexId(3), VertexId(6), VertexId(2), VertexId(1)))
+		}
+	})
+	
+	c.Specify("Two disjoint cycles", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>3>1")
+		ReadDgraphLine(gr, "4>5>6>7>4")
+		components := StronglyConnectedComponents(gr)
+		c.Expect(len(components), Equals, 2)
+		if len(components[0])==3 {
+			c.Expect(components[0], ContainsExactly, Values(VertexId(1), VertexId(2), VertexId(3)))
+			c.Expect(components[1], ContainsExactly, Values(VertexId(4), VertexId(5), VertexId(6), VertexId(7)))
+		} else {
+			c.Expect(components[0], ContainsExactly, Values(VertexId(4), VertexId(5), VertexId(6), VertexId(7)))
+			c.Expect(components[1], ContainsExactly, Values(VertexId(1), VertexId(2), VertexId(3)))
+		}
+	})
+	
+	c.Specify("Cycles connected with an arc", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>3>1")
+		ReadDgraphLine(gr, "4>5>4")
+		ReadDgraphLine(gr, "3>4>6")
+		components := StronglyConnectedComponents(gr)
+		c.Expect(len(components), Equals, 3)
+		// reverse topological order
+		c.Expect(components[0], ContainsExactly, Values(VertexId(6)))
+		c.Expect(components[1], ContainsExactly, Values(VertexId(4), VertexId(5)))
+		c.Expect(components[2], ContainsExactly, Values(VertexId(1), VertexId(2), VertexId(3)))
+	})
+}
+
+func CondensationSpec(c gospec.Context) {
+	c.Specify("Components are contracted", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>3>1")
+		ReadDgraphLine(gr, "3>4>5>4")
+		ReadDgraphLine(gr, "2>5")
+		ReadDgraphLine(gr, "1>6")
+		condensed, componentOf := Condensation(gr)
+		c.Expect(condensed.Order(), Equals, 3)
+		c.Expect(len(componentOf), Equals, gr.Order())
+		c.Expect(componentOf[1], Equals, componentOf[2])
+		c.Expect(componentOf[1], Equals, componentOf[3])
+		c.Expect(componentOf[4], Equals, componentOf[5])
+		c.Expect(componentOf[1]!=componentOf[4], IsTrue)
+		// 3>4 and 2>5 give single arc
+		c.Expect(condensed.ArcsCnt(), Equals, 2)
+		c.Expect(condensed.CheckArc(componentOf[1], componentOf[4]), IsTrue)
+		c.Expect(condensed.CheckArc(componentOf[1], componentOf[6]), IsTrue)
+		c.Expect(IsDAG(condensed), IsTrue)
+		for conn := range condensed.ArcsIter() {
+			c.Expect(conn.Tail < conn.Head, IsTrue)
+		}
+	})
+	
+	c.Specify("Acyclic graph is unchanged", func() {
+		gr := generateDirectedGraph1()
+		condensed, _ := Condensation(gr)
+		c.Expect(condensed.Order(), Equals, gr.Order())
+		c.Expect(condensed.ArcsCnt(), Equals, gr.ArcsCnt())
+		c.Expect(IsDAG(condensed), IsTrue)
+	})
+}
+
+func WeaklyConnectedComponentsSpec(c gospec.Context) {
+	c.Specify("Arcs direction is ignored", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "5>3")
+		ReadDgraphLine(gr, "4>3")
+		ReadDgraphLine(gr, "2>1")
+		gr.AddNode(6)
+		components := WeaklyConnectedComponents(gr)
+		c.Expect(len(components), Equals, 3)
+		c.Expect(components[0], ContainsInOrder, Values(VertexId(1), VertexId(2)))
+		c.Expect(components[1], ContainsInOrder, Values(VertexId(3), VertexId(4), VertexId(5)))
+		c.Expect(components[2], ContainsInOrder, Values(VertexId(6)))
+	})
+	
+	c.Specify("Two independent parts", func() {
+		_, _, gr := genDgr2IndependentSubGr()
+		components := WeaklyConnectedComponents(gr)
+		c.Expect(len(components), Equals, 2)
+		c.Expect(components[0], ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(3), VertexId(4), VertexId(5), VertexId(6)))
+		c.Expect(len(components[1]), Equals, 8)
+	})
+}
+
+func ConnectedComponentsSpec(c gospec.Context) {
+	gr := NewUndirectedMap()
+	ReadUgraphLine(gr, "1-2-3-1")
+	ReadUgraphLine(gr, "4-5-6-4")
+	gr.AddNode(7)
+	
+	c.Specify("Disconnected triangles", func() {
+		components := ConnectedComponents(gr)
+		c.Expect(len(components), Equals, 3)
+		c.Expect(components[0], ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(3)))
+		c.Expect(components[1], ContainsInOrder, Values(VertexId(4), VertexId(5), VertexId(6)))
+		c.Expect(components[2], ContainsInOrder, Values(VertexId(7)))
+	})
+	
+	c.Specify("Triangles joined with an edge", func() {
+		gr.AddEdge(3, 5)
+		components := ConnectedComponents(gr)
+		c.Expect(len(components), Equals, 2)
+		c.Expect(components[0], ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(3), VertexId(4), VertexId(5), VertexId(6)))
+		c.Expect(components[1], ContainsInOrder, Values(VertexId(7)))
+	})
+}
+
+func ArticulationPointsSpec(c gospec.Context) {
+	c.Specify("Two triangles connected with bridge", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-1")
+		ReadUgraphLine(gr, "4-5-6-4")
+		ReadUgraphLine(gr, "3-4")
+		c.Expect(ArticulationPoints(gr), ContainsInOrder, Values(VertexId(3), VertexId(4)))
+	})
+	
+	c.Specify("Root with two children", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "2-1-3")
+		c.Expect(ArticulationPoints(gr), ContainsInOrder, Values(VertexId(1)))
+	})
+	
+	c.Specify("Cycle has no articulation points", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-4-5-1")
+		c.Expect(len(ArticulationPoints(gr)), Equals, 0)
+	})
+	
+	c.Specify("Deep chain", func() {
+		gr := NewUndirectedMap()
+		for i:=1; i<100000; i++ {
+			gr.AddEdge(VertexId(i), VertexId(i+1))
+		}
+		c.Expect(len(ArticulationPoints(gr)), Equals, 99998)
+	})
+}
+
+// Undirected multigraph edges reader, backed by edges list.
+type edgesListReader []Connection
+
+func (edges edgesListReader) EdgesIter() <-chan Connection {
+	ch := make(chan Connection)
+	go func() {
+		for _, edge := range edges {
+			ch <- edge
+		}
+		close(ch)
+	}()
+	return ch
+}
+
+func (edges edgesListReader) EdgesCnt() int {
+	return len(edges)
+}
+
+func (edges edgesListReader) CheckEdge(node1, node2 VertexId) bool {
+	for _, edge := range edges {
+		if (edge.Tail==node1 && edge.Head==node2) || (edge.Tail==node2 && edge.Head==node1) {
+			return true
+		}
+	}
+	return false
+}
+
+func (edges edgesListReader) GetNeighbours(node VertexId) VertexesIterable {
+	neighbours := make(Vertexes, 0, 10)
+	for _, edge := range edges {
+		if edge.Tail==node {
+			neighbours = append(neighbours, edge.Head)
+		} else if edge.Head==node {
+			neighbours = append(neighbours, edge.Tail)
+		}
+	}
+	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:func() <-chan VertexId {
+		ch := make(chan VertexId)
+		go func() {
+			for _, neighbour := range neighbours {
+				ch <- neighbour
+			}
+			close(ch)
+		}()
+		return ch
+	}})
+}
+
+func BridgesSpec(c gospec.Context) {
+	c.Specify("Every edge of chain is a bridge", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "3-1-4-2-5")
+		bridges := Bridges(gr)
+		c.Expect(len(bridges), Equals, 4)
+		c.Expect(bridges[0].String(), Equals, "1->3")
+		c.Expect(bridges[1].String(), Equals, "1->4")
+		c.Expect(bridges[2].String(), Equals, "2->4")
+		c.Expect(bridges[3].String(), Equals, "2->5")
+	})
+	
+	c.Specify("Two triangles connected with bridge", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-1")
+		ReadUgraphLine(gr, "4-5-6-4")
+		ReadUgraphLine(gr, "3-4")
+		bridges := Bridges(gr)
+		c.Expect(len(bridges), Equals, 1)
+		c.Expect(bridges[0].String(), Equals, "3->4")
+	})
+	
+	c.Specify("Parallel edges aren't bridges", func() {
+		gr := edgesListReader{Connection{1, 2}, Connection{2, 3}, Connection{3, 2}}
+		bridges := Bridges(gr)
+		c.Expect(len(bridges), Equals, 1)
+		c.Expect(bridges[0].String(), Equals, "1->2")
+		c.Expect(ArticulationPoints(gr), ContainsInOrder, Values(VertexId(2)))
+	})
+}
+
+func TwoEdgeConnectedComponentsSpec(c gospec.Context) {
+	// two clusters, separated with bridge 3-4, and tail 6-7
+	gr := NewUndirectedMap()
+	ReadUgraphLine(gr, "1-2-3-1")
+	ReadUgraphLine(gr, "4-5-6-4")
+	ReadUgraphLine(gr, "3-4")
+	ReadUgraphLine(gr, "6-7")
+	gr.AddNode(8)
+	
+	c.Specify("Components partition", func() {
+		components := TwoEdgeConnectedComponents(gr)
+		c.Expect(len(components), Equals, 4)
+		c.Expect(components[0], ContainsExactly, Values(VertexId(1), VertexId(2), VertexId(3)))
+		c.Expect(components[1], ContainsExactly, Values(VertexId(4), VertexId(5), VertexId(6)))
+		c.Expect(components[2], ContainsExactly, Values(VertexId(7)))
+		c.Expect(components[3], ContainsExactly, Values(VertexId(8)))
+	})
+	
+	c.Specify("Vertexes separated by bridge", func() {
+		c.Expect(Are2EdgeConnected(gr, 1, 3), IsTrue)
+		c.Expect(Are2EdgeConnected(gr, 4, 6), IsTrue)
+		c.Expect(Are2EdgeConnected(gr, 7, 7), IsTrue)
+		c.Expect(Are2EdgeConnected(gr, 2, 5), IsFalse)
+		c.Expect(Are2EdgeConnected(gr, 6, 7), IsFalse)
+		c.Expect(Are2EdgeConnected(gr, 8, 1), IsFalse)
+		c.Expect(Are2EdgeConnected(gr, 9, 9), IsFalse)
+	})
+	
+	c.Specify("Parallel edges keep vertexes 2-edge-connected", func() {
+		multi := edgesListReader{Connection{1, 2}, Connection{2, 3}, Connection{3, 2}}
+		c.Expect(Are2EdgeConnected(multi, 2, 3), IsTrue)
+		c.Expect(Are2EdgeConnected(multi, 1, 2), IsFalse)
+	})
+}
+
+func BiconnectedComponentsSpec(c gospec.Context) {
+	c.Specify("Two cycles sharing one vertex", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-1")
+		ReadUgraphLine(gr, "3-4-5-6-3")
+		components := BiconnectedComponents(gr)
+		c.Expect(len(components), Equals, 2)
+		c.Expect(len(components[0]), Equals, 3)
+		c.Expect(components[0][0].String(), Equals, "1->2")
+		c.Expect(components[0][1].String(), Equals, "1->3")
+		c.Expect(components[0][2].String(), Equals, "2->3")
+		c.Expect(len(components[1]), Equals, 4)
+		c.Expect(components[1][0].String(), Equals, "3->4")
+		c.Expect(components[1][3].String(), Equals, "5->6")
+	})
+	
+	c.Specify("Bridge is a separate component", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-1")
+		ReadUgraphLine(gr, "4-5-6-4")
+		ReadUgraphLine(gr, "3-4")
+		components := BiconnectedComponents(gr)
+		c.Expect(len(components), Equals, 3)
+		c.Expect(len(components[1]), Equals, 1)
+		c.Expect(components[1][0].String(), Equals, "3->4")
+	})
+	
+	c.Specify("Parallel edges and self-loops", func() {
+		gr := edgesListReader{Connection{1, 2}, Connection{2, 3}, Connection{3, 2}, Connection{3, 3}}
+		components := BiconnectedComponents(gr)
+		c.Expect(len(components), Equals, 2)
+		c.Expect(len(components[0]), Equals, 1)
+		c.Expect(len(components[1]), Equals, 2)
+		c.Expect(components[1][0].String(), Equals, "2->3")
+		c.Expect(components[1][1].String(), Equals, "2->3")
+	})
+	
+	c.Specify("Deep chain", func() {
+		gr := NewUndirectedMap()
+		for i:=1; i<100000; i++ {
+			gr.AddEdge(VertexId(i), VertexId(i+1))
+		}
+		c.Expect(len(BiconnectedComponents(gr)), Equals, 99999)
+	})
+}
+
+func TestComponents(t *testing.T) {
+	r := gospec.NewRunner()
+	r.AddSpec(StronglyConnectedComponentsSpec)
+	r.AddSpec(CondensationSpec)
+	r.AddSpec(WeaklyConnectedComponentsSpec)
+	r.AddSpec(ConnectedComponentsSpec)
+	r.AddSpec(ArticulationPointsSpec)
+	r.AddSpec(BridgesSpec)
+	r.AddSpec(TwoEdgeConnectedComponentsSpec)
+	r.AddSpec(BiconnectedComponentsSpec)
+	gospec.MainGoTest(r, t)
+}
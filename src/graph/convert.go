@@ -0,0 +1,213 @@
+package graph
+
+import (
+	"json"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/StepLg/go-erx/src/erx"
+)
+
+// Directed graph in json format.
+//
+// Field names are matched case-insensitively by json package, so this
+// structure is used to read lower case "vertices" and "arcs" keys.
+type directedGraphJSON struct {
+	Vertices []VertexId
+	Arcs [][]VertexId
+}
+
+// Marshal directed graph to json.
+//
+// Output format is {"vertices":[...],"arcs":[[tail,head],...]}. All vertexes
+// are listed, so isolated vertexes are preserved.
+func MarshalDirectedJSON(gr DirectedGraphReader) ([]byte, os.Error) {
+	arcs := make([][]VertexId, 0, gr.ArcsCnt())
+	for conn := range gr.ArcsIter() {
+		arcs = append(arcs, []VertexId{conn.Tail, conn.Head})
+	}
+	
+	data := map[string]interface{} {
+		"vertices": CollectVertexes(gr),
+		"arcs": arcs,
+	}
+	return json.Marshal(data)
+}
+
+// Unmarshal directed graph from json, produced by MarshalDirectedJSON.
+//
+// Result graph is DirectedMap. Error is returned for invalid json, arcs with
+// not exactly two vertexes and duplicate vertexes or arcs.
+func UnmarshalDirectedJSON(data []byte) (gr DirectedGraph, err os.Error) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			gr = nil
+			err = erx.NewSequent("Unmarshal directed graph from json.", e)
+		}
+	}()
+	
+	var parsed directedGraphJSON
+	if err := json.Unmarshal(data, &parsed); err!=nil {
+		return nil, erx.NewSequent("Unmarshal directed graph from json.", err)
+	}
+	
+	gr = NewDirectedMap()
+	for _, node := range parsed.Vertices {
+		gr.AddNode(node)
+	}
+	for _, arc := range parsed.Arcs {
+		if len(arc)!=2 {
+			err := erx.NewError("Arc must contain exactly two vertexes.")
+			err.AddV("arc", arc)
+			panic(err)
+		}
+		gr.AddArc(arc[0], arc[1])
+	}
+	
+	return gr, nil
+}
+
+// Convert directed graph to adjacency matrix.
+//
+// Returns matrix and vertexes order for it's rows and columns: matrix[i][j] is
+// true if there is an arc from nodes[i] to nodes[j]. Vertexes are sorted by id.
+func ToAdjacencyMatrix(gr DirectedGraphReader) ([][]bool, []VertexId) {
+	nodes, nodesIndex := adjacencyMatrixNodes(gr)
+	matrix := make([][]bool, len(nodes))
+	for i, _ := range matrix {
+		matrix[i] = make([]bool, len(nodes))
+	}
+	for conn := range gr.ArcsIter() {
+		matrix[nodesIndex[conn.Tail]][nodesIndex[conn.Head]] = true
+	}
+	return matrix, nodes
+}
+
+// Convert directed graph to weighted adjacency matrix.
+//
+// Works like ToAdjacencyMatrix, but matrix[i][j] is weightFunc(nodes[i], nodes[j])
+// if there is an arc from nodes[i] to nodes[j], and math.MaxFloat64 otherwise.
+func ToWeightedAdjacencyMatrix(gr DirectedGraphReader, weightFunc ConnectionWeightFunc) ([][]float64, []VertexId) {
+	nodes, nodesIndex := adjacencyMatrixNodes(gr)
+	matrix := make([][]float64, len(nodes))
+	for i, _ := range matrix {
+		matrix[i] = make([]float64, len(nodes))
+		for j, _ := range matrix[i] {
+			matrix[i][j] = math.MaxFloat64
+		}
+	}
+	for conn := range gr.ArcsIter() {
+		matrix[nodesIndex[conn.Tail]][nodesIndex[conn.Head]] = weightFunc(conn.Tail, conn.Head)
+	}
+	return matrix, nodes
+}
+
+// Build directed graph from adjacency matrix.
+//
+// nodes is vertexes order for matrix rows and columns, see ToAdjacencyMatrix.
+// Error is returned if matrix isn't square, it's size doesn't match nodes count
+// or there are duplicate nodes.
+func FromAdjacencyMatrix(matrix [][]bool, nodes []VertexId) (DirectedGraph, os.Error) {
+	rowsLengths := make([]int, len(matrix))
+	for i, row := range matrix {
+		rowsLengths[i] = len(row)
+	}
+	if err := checkAdjacencyMatrix(rowsLengths, nodes); err!=nil {
+		return nil, err
+	}
+	
+	gr := NewDirectedMap()
+	for _, node := range nodes {
+		gr.AddNode(node)
+	}
+	for i, row := range matrix {
+		for j, isArc := range row {
+			if isArc {
+				gr.AddArc(nodes[i], nodes[j])
+			}
+		}
+	}
+	return gr, nil
+}
+
+// Build directed graph and it's weight function from weighted adjacency matrix.
+//
+// Arc exists for every matrix element, except math.MaxFloat64 ones, see
+// ToWeightedAdjacencyMatrix. Weight function returns matrix element for
+// existing arcs and math.MaxFloat64 for all other pairs of nodes. Matrix is
+// validated just like in FromAdjacencyMatrix.
+func FromWeightedAdjacencyMatrix(matrix [][]float64, nodes []VertexId) (DirectedGraph, ConnectionWeightFunc, os.Error) {
+	rowsLengths := make([]int, len(matrix))
+	for i, row := range matrix {
+		rowsLengths[i] = len(row)
+	}
+	if err := checkAdjacencyMatrix(rowsLengths, nodes); err!=nil {
+		return nil, nil, err
+	}
+	
+	gr := NewDirectedMap()
+	nodesIndex := make(map[VertexId]int, len(nodes))
+	for i, node := range nodes {
+		gr.AddNode(node)
+		nodesIndex[node] = i
+	}
+	for i, row := range matrix {
+		for j, weight := range row {
+			if weight!=math.MaxFloat64 {
+				gr.AddArc(nodes[i], nodes[j])
+			}
+		}
+	}
+	weightFunc := func(tail, head VertexId) float64 {
+		i, ok := nodesIndex[tail]
+		if !ok {
+			return math.MaxFloat64
+		}
+		j, ok := nodesIndex[head]
+		if !ok {
+			return math.MaxFloat64
+		}
+		return matrix[i][j]
+	}
+	return gr, weightFunc, nil
+}
+
+// Sorted graph vertexes with their positions.
+func adjacencyMatrixNodes(gr VertexesIterable) ([]VertexId, map[VertexId]int) {
+	nodes := CollectVertexes(gr)
+	sort.Sort(Vertexes(nodes))
+	nodesIndex := make(map[VertexId]int, len(nodes))
+	for i, node := range nodes {
+		nodesIndex[node] = i
+	}
+	return nodes, nodesIndex
+}
+
+func checkAdjacencyMatrix(rowsLengths []int, nodes []VertexId) os.Error {
+	if len(rowsLengths)!=len(nodes) {
+		err := erx.NewError("Matrix rows count doesn't match nodes count.")
+		err.AddV("rows count", len(rowsLengths))
+		err.AddV("nodes count", len(nodes))
+		return err
+	}
+	for i, rowLength := range rowsLengths {
+		if rowLength!=len(nodes) {
+			err := erx.NewError("Matrix isn't square.")
+			err.AddV("row", i)
+			err.AddV("row length", rowLength)
+			err.AddV("rows count", len(rowsLengths))
+			return err
+		}
+	}
+	known := make(map[VertexId]bool, len(nodes))
+	for _, node := range nodes {
+		if _, ok := known[node]; ok {
+			err := erx.NewError("Duplicate node.")
+			err.AddV("node", node)
+			return err
+		}
+		known[node] = true
+	}
+	return nil
+}
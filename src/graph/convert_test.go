@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"math"
+	"testing"
+	"github.com/orfjackal/gospec/src/gospec"
+	. "github.com/orfjackal/gospec/src/gospec"
+)
+
+func DirectedJSONSpec(c gospec.Context) {
+	c.Specify("Round trip", func() {
+		gr := generateDirectedGraph1()
+		gr.AddNode(10)
+		gr.AddArc(5, 1)
+		data, err := MarshalDirectedJSON(gr)
+		c.Expect(err, IsNil)
+		gr2, err := UnmarshalDirectedJSON(data)
+		c.Expect(err, IsNil)
+		c.Expect(DirectedGraphsEquals(gr, gr2), IsTrue)
+		c.Expect(gr2.CheckNode(10), IsTrue)
+		c.Expect(gr2.CheckArc(5, 1), IsTrue)
+		c.Expect(gr2.CheckArc(1, 5), IsFalse)
+	})
+	
+	c.Specify("Read lower case keys", func() {
+		gr, err := UnmarshalDirectedJSON([]byte(`{"vertices":[1,2,3],"arcs":[[2,1]]}`))
+		c.Expect(err, IsNil)
+		c.Expect(gr.Order(), Equals, 3)
+		c.Expect(gr.ArcsCnt(), Equals, 1)
+		c.Expect(gr.CheckArc(2, 1), IsTrue)
+	})
+	
+	c.Specify("Invalid input", func() {
+		_, err := UnmarshalDirectedJSON([]byte(`{"vertices":[1,2],"arcs":[[1,2,3]]}`))
+		c.Expect(err, Not(IsNil))
+		_, err = UnmarshalDirectedJSON([]byte(`{"vertices":[1,1]}`))
+		c.Expect(err, Not(IsNil))
+		_, err = UnmarshalDirectedJSON([]byte(`not a json`))
+		c.Expect(err, Not(IsNil))
+	})
+}
+
+func AdjacencyMatrixSpec(c gospec.Context) {
+	c.Specify("Boolean matrix for sparse vertexes", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "10>30>20")
+		gr.AddNode(40)
+		matrix, nodes := ToAdjacencyMatrix(gr)
+		c.Expect(nodes, ContainsInOrder, Values(VertexId(10), VertexId(20), VertexId(30), VertexId(40)))
+		c.Expect(matrix[0][2], IsTrue)
+		c.Expect(matrix[2][1], IsTrue)
+		c.Expect(matrix[2][0], IsFalse)
+		c.Expect(matrix[3][3], IsFalse)
+		
+		gr2, err := FromAdjacencyMatrix(matrix, nodes)
+		c.Expect(err, IsNil)
+		c.Expect(DirectedGraphsEquals(gr, gr2), IsTrue)
+	})
+	
+	c.Specify("Weighted matrix", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>3")
+		weightFunc := func(tail, head VertexId) float64 {
+			return float64(tail) * 1.5
+		}
+		matrix, nodes := ToWeightedAdjacencyMatrix(gr, weightFunc)
+		c.Expect(matrix[0][1], Equals, 1.5)
+		c.Expect(matrix[1][2], Equals, 3.0)
+		c.Expect(matrix[0][2], Equals, math.MaxFloat64)
+		
+		gr2, weightFunc2, err := FromWeightedAdjacencyMatrix(matrix, nodes)
+		c.Expect(err, IsNil)
+		c.Expect(DirectedGraphsEquals(gr, gr2), IsTrue)
+		c.Expect(weightFunc2(2, 3), Equals, 3.0)
+		c.Expect(weightFunc2(3, 1), Equals, math.MaxFloat64)
+	})
+	
+	c.Specify("Invalid matrix", func() {
+		_, err := FromAdjacencyMatrix([][]bool{[]bool{false, true}, []bool{false}}, []VertexId{1, 2})
+		c.Expect(err, Not(IsNil))
+		_, err = FromAdjacencyMatrix([][]bool{[]bool{false}}, []VertexId{1, 2})
+		c.Expect(err, Not(IsNil))
+		_, err = FromAdjacencyMatrix([][]bool{[]bool{false, false}, []bool{false, false}}, []VertexId{1, 1})
+		c.Expect(err, Not(IsNil))
+		_, _, err = FromWeightedAdjacencyMatrix([][]float64{[]float64{1.0, 2.0}}, []VertexId{1})
+		c.Expect(err, Not(IsNil))
+	})
+}
+
+func TestConvert(t *testing.T) {
+	r := gospec.NewRunner()
+	r.AddSpec(DirectedJSONSpec)
+	r.AddSpec(AdjacencyMatrixSpec)
+	gospec.MainGoTest(r, t)
+}
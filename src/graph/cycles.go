@@ -0,0 +1,278 @@
+package graph
+
+import (
+	"math"
+	"sort"
+
+	"github.com/StepLg/go-erx/src/erx"
+)
+
+// Check if directed graph has cycles.
+//
+// Performs depth-first search with white/grey/black nodes coloring. When arc
+// to a grey node (back arc) is found, the cycle is returned: there is an arc
+// from each vertex to the next one, and from the last vertex to the first one.
+// Self-loop is a cycle of single vertex.
+//
+// For acyclic graph returns (false, nil).
+func HasDirectedCycle(gr DirectedGraphReader) (bool, []VertexId) {
+	neighboursExtractor := NewDgraphOutNeighboursExtractor(gr)
+	// black nodes: all accessible nodes are processed
+	processed := make(map[VertexId]bool)
+	// grey nodes with their positions in stack
+	stackPos := make(map[VertexId]int)
+	stack := make([]dfsFrame, 0, 10)
+	
+	for _, startNode := range CollectVertexes(gr) {
+		if _, ok := processed[startNode]; ok {
+			continue
+		}
+		stackPos[startNode] = 0
+		stack = append(stack, dfsFrame{
+			node: startNode,
+			neighbours: CollectVertexes(neighboursExtractor.GetOutNeighbours(startNode)),
+		})
+		for len(stack)>0 {
+			top := &stack[len(stack)-1]
+			if top.pos==len(top.neighbours) {
+				processed[top.node] = true
+				stackPos[top.node] = 0, false
+				stack = stack[0:len(stack)-1]
+				continue
+			}
+			nextNode := top.neighbours[top.pos]
+			top.pos++
+			if pos, ok := stackPos[nextNode]; ok {
+				// back arc: cycle is the part of stack from nextNode to the top
+				cycle := make([]VertexId, len(stack)-pos)
+				for i:=pos; i<len(stack); i++ {
+					cycle[i-pos] = stack[i].node
+				}
+				return true, cycle
+			}
+			if _, ok := processed[nextNode]; ok {
+				continue
+			}
+			stackPos[nextNode] = len(stack)
+			stack = append(stack, dfsFrame{
+				node: nextNode,
+				neighbours: CollectVertexes(neighboursExtractor.GetOutNeighbours(nextNode)),
+			})
+		}
+	}
+	
+	return false, nil
+}
+
+// Check if directed graph is acyclic.
+//
+// Same depth-first search as in HasDirectedCycle, but it stops on the first
+// back arc without restoring the cycle, and only node colors are kept.
+// Self-loop is a cycle, so graph with self-loop isn't acyclic.
+func IsDAG(gr DirectedGraphReader) bool {
+	neighboursExtractor := NewDgraphOutNeighboursExtractor(gr)
+	// node in map with false value - grey color, and with true value - black color
+	status := make(map[VertexId]bool)
+	stack := make([]dfsFrame, 0, 10)
+	
+	for _, startNode := range CollectVertexes(gr) {
+		if _, ok := status[startNode]; ok {
+			continue
+		}
+		status[startNode] = false
+		stack = append(stack, dfsFrame{
+			node: startNode,
+			neighbours: CollectVertexes(neighboursExtractor.GetOutNeighbours(startNode)),
+		})
+		for len(stack)>0 {
+			top := &stack[len(stack)-1]
+			if top.pos==len(top.neighbours) {
+				status[top.node] = true
+				stack = stack[0:len(stack)-1]
+				continue
+			}
+			nextNode := top.neighbours[top.pos]
+			top.pos++
+			if isBlack, ok := status[nextNode]; ok {
+				if !isBlack {
+					// back arc
+					return false
+				}
+				continue
+			}
+			status[nextNode] = false
+			stack = append(stack, dfsFrame{
+				node: nextNode,
+				neighbours: CollectVertexes(neighboursExtractor.GetOutNeighbours(nextNode)),
+			})
+		}
+	}
+	
+	return true
+}
+
+// Shortest cycle length (girth) of unweighted undirected graph
+//
+// Breadth-first search is done from each vertex. Non-tree edge between u and
+// w closes cycle of length dist[u]+dist[w]+1 through search root, and the
+// minimal one among all roots is always a simple cycle. Edges are
+// distinguished by their ids, so parallel edges give cycle of length 2 and
+// self-loop gives cycle of single vertex. Takes O(V*E) time.
+//
+// Returns girth and cycle vertexes in cycle order. For acyclic graph (forest)
+// returns (-1, nil).
+func Girth(gr UndirectedGraphEdgesReader) (int, []VertexId) {
+	adjacent, _ := undirectedEdgesAdjacency(gr)
+	roots := make([]VertexId, 0, len(adjacent))
+	for node := range adjacent {
+		roots = append(roots, node)
+	}
+	sort.Sort(Vertexes(roots))
+	
+	girth := -1
+	var girthCycle []VertexId
+	for _, root := range roots {
+		dist := map[VertexId]int{root:0}
+		prev := make(map[VertexId]VertexId)
+		// id of edge to previous node in search tree
+		prevEdge := map[VertexId]int{root:-1}
+		queue := []VertexId{root}
+		for len(queue)>0 {
+			curNode := queue[0]
+			queue = queue[1:]
+			if girth!=-1 && 2*dist[curNode]+1>=girth {
+				// all cycles closed from here aren't shorter than found one
+				break
+			}
+			for _, edge := range adjacent[curNode] {
+				if edge.edgeId==prevEdge[curNode] {
+					continue
+				}
+				if nextDist, ok := dist[edge.node]; ok {
+					if cycleLen := dist[curNode] + nextDist + 1; girth==-1 || cycleLen<girth {
+						girth = cycleLen
+						girthCycle = girthCycleFromTree(prev, root, curNode, edge.node)
+					}
+					continue
+				}
+				dist[edge.node] = dist[curNode] + 1
+				prev[edge.node] = curNode
+				prevEdge[edge.node] = edge.edgeId
+				queue = append(queue, edge.node)
+			}
+		}
+	}
+	
+	return girth, girthCycle
+}
+
+// Cycle from root to u by search tree, then by edge u-w and from w back to
+// root by search tree.
+func girthCycleFromTree(prev map[VertexId]VertexId, root, u, w VertexId) []VertexId {
+	cycle := pathFromPredecessors(prev, root, u)
+	if u==w {
+		return cycle
+	}
+	backPath := pathFromPredecessors(prev, root, w)
+	for i:=len(backPath)-1; i>0; i-- {
+		cycle = append(cycle, backPath[i])
+	}
+	return cycle
+}
+
+// Directed cycle with minimal mean arc weight with Karp algorithm
+//
+// dist[k][v] is minimal weight of walk with exactly k arcs, which ends in v and
+// starts anywhere. Minimal mean is min over v of max over k of
+// (dist[n][v]-dist[k][v])/(n-k), and any cycle in the n-arcs walk to the best
+// v has this mean weight. Takes O(V*E) time and O(V^2) memory.
+//
+// Returns minimal mean weight and cycle vertexes: there is an arc from each
+// vertex to the next one, and from the last vertex to the first one. For
+// acyclic graph returns (0, nil, false).
+func MinimumMeanCycle(gr DirectedGraphReader, weightFunc ConnectionWeightFunc) (float64, []VertexId, bool) {
+	nodes := SortedVertexes(gr)
+	n := len(nodes)
+	nodesIndex := make(map[VertexId]int, n)
+	for i, node := range nodes {
+		nodesIndex[node] = i
+	}
+	type weightedArc struct {
+		tail, head int
+		weight float64
+	}
+	arcs := make([]weightedArc, 0, n)
+	for conn := range gr.ArcsIter() {
+		arcs = append(arcs, weightedArc{nodesIndex[conn.Tail], nodesIndex[conn.Head], weightFunc(conn.Tail, conn.Head)})
+	}
+	
+	// math.MaxFloat64 means there is no walk
+	dist := make([][]float64, n+1)
+	prev := make([][]int, n+1)
+	dist[0] = make([]float64, n)
+	for k:=1; k<=n; k++ {
+		dist[k] = make([]float64, n)
+		prev[k] = make([]int, n)
+		for v:=0; v<n; v++ {
+			dist[k][v] = math.MaxFloat64
+		}
+		for _, arc := range arcs {
+			if dist[k-1][arc.tail]==math.MaxFloat64 {
+				continue
+			}
+			if walkWeight := dist[k-1][arc.tail] + arc.weight; walkWeight < dist[k][arc.head] {
+				dist[k][arc.head] = walkWeight
+				prev[k][arc.head] = arc.tail
+			}
+		}
+	}
+	
+	found := false
+	bestMean := 0.0
+	bestNode := 0
+	for v:=0; v<n; v++ {
+		if dist[n][v]==math.MaxFloat64 {
+			continue
+		}
+		maxMean := -math.MaxFloat64
+		for k:=0; k<n; k++ {
+			if dist[k][v]==math.MaxFloat64 {
+				continue
+			}
+			if mean := (dist[n][v] - dist[k][v]) / float64(n - k); mean > maxMean {
+				maxMean = mean
+			}
+		}
+		if !found || maxMean < bestMean {
+			found = true
+			bestMean = maxMean
+			bestNode = v
+		}
+	}
+	if !found {
+		return 0.0, nil, false
+	}
+	
+	// restoring walk backward till the first repeated vertex
+	walk := make([]int, n+1)
+	walkLevel := make(map[int]int)
+	v := bestNode
+	for k:=n; k>=0; k-- {
+		walk[k] = v
+		if level, ok := walkLevel[v]; ok {
+			cycle := make([]VertexId, 0, level-k)
+			for i:=k; i<level; i++ {
+				cycle = append(cycle, nodes[walk[i]])
+			}
+			return bestMean, cycle, true
+		}
+		walkLevel[v] = k
+		if k>0 {
+			v = prev[k][v]
+		}
+	}
+	
+	err := erx.NewError("Can't find cycle in walk with n arcs.")
+	err.AddV("walk", walk)
+	panic(err)
+}
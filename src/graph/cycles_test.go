@@ -0,0 +1,152 @@
+package graph
+
+import (
+	"testing"
+	"github.com/orfjackal/gospec/src/gospec"
+	. "github.com/orfjackal/gospec/src/gospec"
+)
+
+func HasDirectedCycleSpec(c gospec.Context) {
+	c.Specify("Acyclic graph", func() {
+		hasCycle, cycle := HasDirectedCycle(generateDirectedGraph1())
+		c.Expect(hasCycle, IsFalse)
+		c.Expect(cycle, IsNil)
+	})
+	
+	c.Specify("Self-loop", func() {
+		gr := NewDirectedMap()
+		gr.AddArc(1, 2)
+		gr.AddArc(2, 2)
+		hasCycle, cycle := HasDirectedCycle(gr)
+		c.Expect(hasCycle, IsTrue)
+		c.Expect(cycle, ContainsExactly, Values(VertexId(2)))
+	})
+	
+	c.Specify("Long cycle in a bigger DAG", func() {
+		gr := generateDirectedGraph1()
+		ReadDgraphLine(gr, "5>7>8>9>4")
+		ReadDgraphLine(gr, "6>10")
+		hasCycle, cycle := HasDirectedCycle(gr)
+		c.Expect(hasCycle, IsTrue)
+		c.Expect(cycle, ContainsExactly, Values(VertexId(4), VertexId(5), VertexId(7), VertexId(8), VertexId(9)))
+		c.Expect(ContainDirectedPath(gr, append(cycle, cycle[0]), true), IsTrue)
+	})
+}
+
+func IsDAGSpec(c gospec.Context) {
+	c.Specify("Acyclic graph", func() {
+		c.Expect(IsDAG(generateDirectedGraph1()), IsTrue)
+		c.Expect(IsDAG(NewDirectedMap()), IsTrue)
+	})
+	
+	c.Specify("Self-loop", func() {
+		gr := generateDirectedGraph1()
+		gr.AddArc(3, 3)
+		c.Expect(IsDAG(gr), IsFalse)
+	})
+	
+	c.Specify("Cycle not reachable from sources", func() {
+		gr := generateDirectedGraph1()
+		ReadDgraphLine(gr, "7>8>9>7")
+		c.Expect(IsDAG(gr), IsFalse)
+	})
+	
+	c.Specify("Agrees with HasDirectedCycle", func() {
+		gr := generateDirectedGraph1()
+		ReadDgraphLine(gr, "5>7>8>9>4")
+		hasCycle, _ := HasDirectedCycle(gr)
+		c.Expect(IsDAG(gr), Equals, !hasCycle)
+	})
+}
+
+func GirthSpec(c gospec.Context) {
+	c.Specify("Triangle", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-1")
+		girth, cycle := Girth(gr)
+		c.Expect(girth, Equals, 3)
+		c.Expect(cycle, ContainsExactly, Values(VertexId(1), VertexId(2), VertexId(3)))
+	})
+	
+	c.Specify("Forest", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-4")
+		ReadUgraphLine(gr, "2-5")
+		ReadUgraphLine(gr, "6-7")
+		girth, cycle := Girth(gr)
+		c.Expect(girth, Equals, -1)
+		c.Expect(cycle, IsNil)
+	})
+	
+	c.Specify("Shortest of several cycles", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-4-5-6-1")
+		ReadUgraphLine(gr, "3-7-8-9-3")
+		ReadUgraphLine(gr, "9-10")
+		girth, cycle := Girth(gr)
+		c.Expect(girth, Equals, 4)
+		c.Expect(len(cycle), Equals, 4)
+		c.Expect(cycle, ContainsExactly, Values(VertexId(3), VertexId(7), VertexId(8), VertexId(9)))
+		c.Expect(ContainUndirectedPath(gr, append(cycle, cycle[0]), true), IsTrue)
+	})
+	
+	c.Specify("Grid", func() {
+		girth, cycle := Girth(GridGraph(3, 3))
+		c.Expect(girth, Equals, 4)
+		c.Expect(len(cycle), Equals, 4)
+	})
+}
+
+func MinimumMeanCycleSpec(c gospec.Context) {
+	weights := map[VertexId]map[VertexId]float64{
+		1: map[VertexId]float64{2:1.0},
+		2: map[VertexId]float64{3:2.0, 4:1.0},
+		3: map[VertexId]float64{1:6.0},
+		4: map[VertexId]float64{2:9.0, 5:0.0},
+	}
+	weightFunc := func(tail, head VertexId) float64 {
+		return weights[tail][head]
+	}
+	
+	c.Specify("Cycle with known mean weight", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>3>1")
+		mean, cycle, ok := MinimumMeanCycle(gr, weightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(mean, Equals, 3.0)
+		c.Expect(cycle, ContainsExactly, Values(VertexId(1), VertexId(2), VertexId(3)))
+		c.Expect(ContainDirectedPath(gr, append(cycle, cycle[0]), true), IsTrue)
+	})
+	
+	c.Specify("Best of two cycles", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>3>1")
+		ReadDgraphLine(gr, "2>4>2")
+		ReadDgraphLine(gr, "4>5")
+		mean, cycle, ok := MinimumMeanCycle(gr, weightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(mean, Equals, 3.0)
+		c.Expect(cycle, ContainsExactly, Values(VertexId(1), VertexId(2), VertexId(3)))
+		
+		weights[4][2] = 3.0
+		mean, cycle, ok = MinimumMeanCycle(gr, weightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(mean, Equals, 2.0)
+		c.Expect(cycle, ContainsExactly, Values(VertexId(2), VertexId(4)))
+	})
+	
+	c.Specify("Acyclic graph", func() {
+		_, cycle, ok := MinimumMeanCycle(generateDirectedGraph1(), SimpleWeightFunc)
+		c.Expect(ok, IsFalse)
+		c.Expect(cycle, IsNil)
+	})
+}
+
+func TestCycles(t *testing.T) {
+	r := gospec.NewRunner()
+	r.AddSpec(HasDirectedCycleSpec)
+	r.AddSpec(IsDAGSpec)
+	r.AddSpec(GirthSpec)
+	r.AddSpec(MinimumMeanCycleSpec)
+	gospec.MainGoTest(r, t)
+}
@@ -0,0 +1,236 @@
+package graph
+
+import (
+	"math"
+	"sort"
+
+	"github.com/StepLg/go-erx/src/erx"
+)
+
+// Check if undirected graph has Eulerian path: trail, which uses every edge
+// exactly once.
+//
+// All vertexes with edges must be connected, and count of odd degree vertexes
+// must be 0 or 2. Isolated vertexes are ignored. Graph without edges has no
+// Eulerian path.
+func HasEulerianPath(gr UndirectedGraphEdgesReader) bool {
+	oddCnt, ok := eulerianCheck(gr)
+	return ok && (oddCnt==0 || oddCnt==2)
+}
+
+// Check if undirected graph has Eulerian circuit: closed trail, which uses
+// every edge exactly once.
+//
+// All vertexes with edges must be connected, and all degrees must be even.
+// Isolated vertexes are ignored. Graph without edges has no Eulerian circuit.
+func HasEulerianCircuit(gr UndirectedGraphEdgesReader) bool {
+	oddCnt, ok := eulerianCheck(gr)
+	return ok && oddCnt==0
+}
+
+// Find Eulerian path in undirected graph with Hierholzer algorithm.
+//
+// If there are two odd degree vertexes, path starts in one of them and ends
+// in another. Otherwise path is a circuit: it starts and ends in the same
+// vertex. Returns nil if there is no Eulerian path, see HasEulerianPath.
+func FindEulerianPath(gr UndirectedGraphEdgesReader) []VertexId {
+	if !HasEulerianPath(gr) {
+		return nil
+	}
+	
+	adjacent, edgesCnt := undirectedEdgesAdjacency(gr)
+	
+	nodes := make(Vertexes, 0, len(adjacent))
+	for node, _ := range adjacent {
+		nodes = append(nodes, node)
+	}
+	sort.Sort(nodes)
+	start := nodes[0]
+	for _, node := range nodes {
+		if len(adjacent[node])%2==1 {
+			start = node
+			break
+		}
+	}
+	
+	return hierholzerPath(adjacent, edgesCnt, start)
+}
+
+// Hierholzer algorithm on adjacency lists with edge ids.
+//
+// Graph must have Eulerian path, which starts in start node.
+func hierholzerPath(adjacent map[VertexId][]adjacentEdge, edgesCnt int, start VertexId) []VertexId {
+	used := make([]bool, edgesCnt)
+	// position of next adjacent edge to check for each node
+	nextEdge := make(map[VertexId]int)
+	stack := make([]VertexId, 0, edgesCnt+1)
+	stack = append(stack, start)
+	path := make([]VertexId, 0, edgesCnt+1)
+	for len(stack)>0 {
+		curNode := stack[len(stack)-1]
+		edges := adjacent[curNode]
+		pos := nextEdge[curNode]
+		for pos<len(edges) && used[edges[pos].edgeId] {
+			pos++
+		}
+		nextEdge[curNode] = pos
+		if pos<len(edges) {
+			used[edges[pos].edgeId] = true
+			stack = append(stack, edges[pos].node)
+		} else {
+			stack = stack[0:len(stack)-1]
+			path = append(path, curNode)
+		}
+	}
+	
+	return path
+}
+
+// Maximal count of odd degree vertexes in ChinesePostmanRoute, it's matching
+// table has 2^k entries.
+const ChinesePostmanMaxOddVertexes = 20
+
+// Solve Chinese Postman Problem: the shortest closed walk, which uses every
+// edge of undirected graph at least once.
+//
+// Odd degree vertexes are paired with minimum weight perfect matching, where
+// pair weight is the shortest path weight between vertexes (see
+// ShortestPathDijkstra). Edges of these paths are duplicated, so all degrees
+// become even, and then Eulerian circuit is found with Hierholzer algorithm.
+// For Eulerian graph the circuit itself is returned. Matching is exact and
+// takes O(2^k * k) time and O(2^k) memory for k odd degree vertexes, so
+// function panics if k is greater than ChinesePostmanMaxOddVertexes.
+// weightFunc must be symmetric and non-negative.
+//
+// Graph must be connected (isolated vertexes are ignored). Returns walk
+// vertexes (the first vertex is equal to the last one) and it's total weight.
+// For disconnected graph or graph without edges returns (nil, 0).
+func ChinesePostmanRoute(gr UndirectedGraphEdgesReader, weightFunc ConnectionWeightFunc) ([]VertexId, float64) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Search Chinese Postman route", e)
+			panic(err)
+		}
+	}()
+	
+	if _, ok := eulerianCheck(gr); !ok {
+		return nil, 0.0
+	}
+	
+	adjacent, edgesCnt := undirectedEdgesAdjacency(gr)
+	totalWeight := 0.0
+	for conn := range gr.EdgesIter() {
+		totalWeight += weightFunc(conn.Tail, conn.Head)
+	}
+	
+	nodes := make(Vertexes, 0, len(adjacent))
+	oddNodes := make(Vertexes, 0, 10)
+	for node, edges := range adjacent {
+		nodes = append(nodes, node)
+		if len(edges)%2==1 {
+			oddNodes = append(oddNodes, node)
+		}
+	}
+	sort.Sort(nodes)
+	sort.Sort(oddNodes)
+	
+	if len(oddNodes)>ChinesePostmanMaxOddVertexes {
+		err := erx.NewError("Too many odd degree vertexes for exact matching.")
+		err.AddV("odd vertexes count", len(oddNodes))
+		err.AddV("limit", ChinesePostmanMaxOddVertexes)
+		panic(err)
+	}
+	if len(oddNodes)>0 {
+		// shortest paths between all pairs of odd vertexes
+		extractor := NewUgraphOutNeighboursExtractor(gr)
+		k := len(oddNodes)
+		paths := make([][][]VertexId, k)
+		pathsWeights := make([][]float64, k)
+		for i:=0; i<k; i++ {
+			paths[i] = make([][]VertexId, k)
+			pathsWeights[i] = make([]float64, k)
+		}
+		for i:=0; i<k; i++ {
+			for j:=i+1; j<k; j++ {
+				path, weight, _ := ShortestPathDijkstra(extractor, oddNodes[i], oddNodes[j], nil, weightFunc)
+				paths[i][j], paths[j][i] = path, path
+				pathsWeights[i][j], pathsWeights[j][i] = weight, weight
+			}
+		}
+		
+		// minimal matching weight of unmatched vertexes set with dynamic
+		// programming: the smallest unmatched vertex is paired with each of
+		// others
+		fullMask := 1<<uint(k) - 1
+		matchWeight := make([]float64, fullMask+1)
+		matchPair := make([]int, fullMask+1)
+		for mask:=1; mask<=fullMask; mask++ {
+			matchWeight[mask] = math.MaxFloat64
+			first := 0
+			for mask&(1<<uint(first))==0 {
+				first++
+			}
+			for j:=first+1; j<k; j++ {
+				if mask&(1<<uint(j))==0 {
+					continue
+				}
+				rest := mask &^ (1<<uint(first) | 1<<uint(j))
+				if matchWeight[rest]==math.MaxFloat64 {
+					continue
+				}
+				if weight := pathsWeights[first][j] + matchWeight[rest]; weight<matchWeight[mask] {
+					matchWeight[mask] = weight
+					matchPair[mask] = j
+				}
+			}
+		}
+		
+		// duplicating edges of matched pairs paths
+		for mask:=fullMask; mask!=0; {
+			first := 0
+			for mask&(1<<uint(first))==0 {
+				first++
+			}
+			j := matchPair[mask]
+			path := paths[first][j]
+			for pos:=1; pos<len(path); pos++ {
+				adjacent[path[pos-1]] = append(adjacent[path[pos-1]], adjacentEdge{path[pos], edgesCnt})
+				adjacent[path[pos]] = append(adjacent[path[pos]], adjacentEdge{path[pos-1], edgesCnt})
+				edgesCnt++
+			}
+			mask &^= 1<<uint(first) | 1<<uint(j)
+		}
+		totalWeight += matchWeight[fullMask]
+	}
+	
+	return hierholzerPath(adjacent, edgesCnt, nodes[0]), totalWeight
+}
+
+// Count odd degree vertexes and check that all vertexes with edges are
+// connected.
+//
+// Returns false if graph has no edges or it isn't connected.
+func eulerianCheck(gr UndirectedGraphEdgesReader) (int, bool) {
+	degrees := make(map[VertexId]int)
+	sets := newVertexesDisjointSets()
+	for conn := range gr.EdgesIter() {
+		degrees[conn.Tail]++
+		degrees[conn.Head]++
+		sets.Union(conn.Tail, conn.Head)
+	}
+	if len(degrees)==0 {
+		return 0, false
+	}
+	
+	oddCnt := 0
+	componentsCnt := 0
+	for node, degree := range degrees {
+		if degree%2==1 {
+			oddCnt++
+		}
+		if sets.Find(node)==node {
+			componentsCnt++
+		}
+	}
+	return oddCnt, componentsCnt==1
+}
@@ -0,0 +1,158 @@
+package graph
+
+import (
+	"testing"
+	"github.com/orfjackal/gospec/src/gospec"
+	. "github.com/orfjackal/gospec/src/gospec"
+)
+
+// Check that path uses every edge of graph exactly once.
+func checkEulerianPath(c gospec.Context, gr UndirectedGraphReader, path []VertexId) {
+	c.Expect(len(path), Equals, gr.EdgesCnt()+1)
+	used := make(map[VertexId]map[VertexId]bool)
+	for i:=1; i<len(path); i++ {
+		edge := NewUndirectedConnection(path[i-1], path[i]).Connection
+		c.Expect(gr.CheckEdge(edge.Tail, edge.Head), IsTrue)
+		if _, ok := used[edge.Tail]; !ok {
+			used[edge.Tail] = make(map[VertexId]bool)
+		}
+		c.Expect(used[edge.Tail][edge.Head], IsFalse)
+		used[edge.Tail][edge.Head] = true
+	}
+}
+
+func EulerianPathSpec(c gospec.Context) {
+	c.Specify("Path, but no circuit", func() {
+		gr := NewUndirectedMap()
+		// house: square with roof
+		ReadUgraphLine(gr, "1-2-3-4-1-3")
+		ReadUgraphLine(gr, "3-5-4")
+		c.Expect(HasEulerianPath(gr), IsTrue)
+		c.Expect(HasEulerianCircuit(gr), IsFalse)
+		path := FindEulerianPath(gr)
+		checkEulerianPath(c, gr, path)
+		ends := []VertexId{path[0], path[len(path)-1]}
+		c.Expect(ends, ContainsExactly, Values(VertexId(1), VertexId(4)))
+	})
+	
+	c.Specify("Circuit", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-1-4-5-1")
+		gr.AddNode(10)
+		c.Expect(HasEulerianPath(gr), IsTrue)
+		c.Expect(HasEulerianCircuit(gr), IsTrue)
+		path := FindEulerianPath(gr)
+		checkEulerianPath(c, gr, path)
+		c.Expect(path[0], Equals, path[len(path)-1])
+	})
+	
+	c.Specify("Too many odd vertexes", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2")
+		ReadUgraphLine(gr, "1-3")
+		ReadUgraphLine(gr, "1-4")
+		c.Expect(HasEulerianPath(gr), IsFalse)
+		c.Expect(FindEulerianPath(gr), IsNil)
+	})
+	
+	c.Specify("Disconnected edges", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-1")
+		ReadUgraphLine(gr, "4-5-6-4")
+		c.Expect(HasEulerianPath(gr), IsFalse)
+		c.Expect(HasEulerianCircuit(gr), IsFalse)
+	})
+}
+
+func ChinesePostmanRouteSpec(c gospec.Context) {
+	// checks that route is closed, goes by graph edges and uses each of them
+	checkRoute := func(gr UndirectedGraphReader, route []VertexId) {
+		c.Expect(route[0], Equals, route[len(route)-1])
+		c.Expect(ContainUndirectedPath(gr, route, true), IsTrue)
+		for conn := range gr.EdgesIter() {
+			used := false
+			for i:=1; i<len(route); i++ {
+				if (route[i-1]==conn.Tail && route[i]==conn.Head) || (route[i-1]==conn.Head && route[i]==conn.Tail) {
+					used = true
+				}
+			}
+			c.Expect(used, IsTrue)
+		}
+	}
+	
+	c.Specify("Eulerian graph", func() {
+		gr := CycleGraph(4)
+		route, weight := ChinesePostmanRoute(gr, SimpleWeightFunc)
+		c.Expect(weight, Equals, 4.0)
+		c.Expect(len(route), Equals, 5)
+		checkRoute(gr, route)
+	})
+	
+	c.Specify("Path graph is walked twice", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3")
+		route, weight := ChinesePostmanRoute(gr, SimpleWeightFunc)
+		c.Expect(weight, Equals, 4.0)
+		c.Expect(len(route), Equals, 5)
+		checkRoute(gr, route)
+	})
+	
+	c.Specify("Cheapest path between odd vertexes is duplicated", func() {
+		// square with diagonal 1-3, odd vertexes are 1 and 3
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-4-1-3")
+		weightFunc := func(tail, head VertexId) float64 {
+			if (tail==1 && head==3) || (tail==3 && head==1) {
+				return 5.0
+			}
+			return 1.0
+		}
+		route, weight := ChinesePostmanRoute(gr, weightFunc)
+		// 1-2-3 path is cheaper than diagonal
+		c.Expect(weight, Equals, 9.0 + 2.0)
+		c.Expect(len(route), Equals, 8)
+		checkRoute(gr, route)
+	})
+	
+	c.Specify("Matching of four odd vertexes", func() {
+		gr := GridGraph(2, 3)
+		route, weight := ChinesePostmanRoute(gr, SimpleWeightFunc)
+		// odd vertexes 1 and 4 are connected by edge
+		c.Expect(weight, Equals, 8.0)
+		checkRoute(gr, route)
+		
+		gr = GridGraph(3, 3)
+		route, weight = ChinesePostmanRoute(gr, SimpleWeightFunc)
+		// odd vertexes 1, 3, 5, 7 are paired with paths of 2 edges
+		c.Expect(weight, Equals, 12.0 + 4.0)
+		checkRoute(gr, route)
+	})
+	
+	c.Specify("Disconnected graph", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2")
+		ReadUgraphLine(gr, "3-4")
+		route, weight := ChinesePostmanRoute(gr, SimpleWeightFunc)
+		c.Expect(route, IsNil)
+		c.Expect(weight, Equals, 0.0)
+	})
+	
+	c.Specify("Too many odd vertexes", func() {
+		defer func() {
+			c.Expect(recover(), Not(IsNil))
+		}()
+		// star with 22 odd leaves and even center
+		gr := NewUndirectedMap()
+		for i:=1; i<=ChinesePostmanMaxOddVertexes+2; i++ {
+			gr.AddEdge(0, VertexId(i))
+		}
+		ChinesePostmanRoute(gr, SimpleWeightFunc)
+	})
+}
+
+func TestEuler(t *testing.T) {
+	r := gospec.NewRunner()
+	r.AddSpec(EulerianPathSpec)
+	r.AddSpec(ChinesePostmanRouteSpec)
+	gospec.MainGoTest(r, t)
+}
@@ -100,6 +100,34 @@ func (filter *DirectedGraphArcsFilter) ArcsIter() <-chan Connection {
 	return ch
 }
 
+// Getting arcs count in graph
+//
+// Filtered arcs, which exist in graph, are subtracted from underlying graph
+// arcs count. Nodes of all filtered arcs must exist in graph or error will be
+// returned.
+func (filter *DirectedGraphArcsFilter) ArcsCnt() int {
+	cnt := filter.DirectedGraphArcsReader.ArcsCnt()
+	for i, conn := range filter.arcs {
+		if filter.isArcFilteringBefore(i, conn) {
+			continue
+		}
+		if filter.DirectedGraphArcsReader.CheckArc(conn.Tail, conn.Head) {
+			cnt--
+		}
+	}
+	return cnt
+}
+
+// Check if the same arc is in filtering list before position pos
+func (filter *DirectedGraphArcsFilter) isArcFilteringBefore(pos int, conn Connection) bool {
+	for i:=0; i<pos; i++ {
+		if filter.arcs[i].Tail==conn.Tail && filter.arcs[i].Head==conn.Head {
+			return true
+		}
+	}
+	return false
+}
+
 func (filter *DirectedGraphArcsFilter) IsArcFiltering(tail, head VertexId) bool {
 	for _, filteringConnection := range filter.arcs {
 		if filteringConnection.Head==head && filteringConnection.Tail==tail {
@@ -197,6 +225,36 @@ func (filter *UndirectedGraphEdgesFilter) EdgesIter() <-chan Connection {
 	return ch
 }
 
+// Getting edges count in graph
+//
+// Filtered edges, which exist in graph, are subtracted from underlying graph
+// edges count. Nodes of all filtered edges must exist in graph or error will
+// be returned.
+func (filter *UndirectedGraphEdgesFilter) EdgesCnt() int {
+	cnt := filter.UndirectedGraphEdgesReader.EdgesCnt()
+	for i, conn := range filter.edges {
+		if filter.isEdgeFilteringBefore(i, conn) {
+			continue
+		}
+		if filter.UndirectedGraphEdgesReader.CheckEdge(conn.Tail, conn.Head) {
+			cnt--
+		}
+	}
+	return cnt
+}
+
+// Check if the same edge is in filtering list before position pos
+//
+// Tails of filtering edges are always not greater than heads.
+func (filter *UndirectedGraphEdgesFilter) isEdgeFilteringBefore(pos int, conn Connection) bool {
+	for i:=0; i<pos; i++ {
+		if filter.edges[i].Tail==conn.Tail && filter.edges[i].Head==conn.Head {
+			return true
+		}
+	}
+	return false
+}
+
 func (filter *UndirectedGraphEdgesFilter) IsEdgeFiltering(tail, head VertexId) bool {
 	if head<tail {
 		tail, head = head, tail
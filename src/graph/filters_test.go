@@ -16,6 +16,11 @@ func DirectedGraphArcsFilterSpec(c gospec.Context) {
 	gr.AddArc(1, 6)
 	gr.AddArc(2, 6)
 
+	c.Specify("Duplicate and missing filtered arcs aren't counted", func() {
+		f := NewDirectedGraphArcsFilter(gr, []Connection{Connection{2, 3}, Connection{2, 3}, Connection{3, 2}, Connection{1, 2}})
+		c.Expect(f.ArcsCnt(), Equals, 5)
+	})
+	
 	c.Specify("Single filtered arc", func() {
 		ftail := VertexId(2)
 		fhead := VertexId(3)
@@ -25,6 +30,10 @@ func DirectedGraphArcsFilterSpec(c gospec.Context) {
 			c.Expect(f.CheckArc(ftail, fhead), IsFalse)
 		})
 		
+		c.Specify("shouldn't be counted", func() {
+			c.Expect(f.ArcsCnt(), Equals, 6)
+		})
+		
 		c.Specify("shouldn't appear in accessors", func() {
 			c.Expect(CollectVertexes(f.GetAccessors(VertexId(ftail))), Not(Contains), fhead)
 		})
@@ -64,6 +73,10 @@ func UndirectedGraphEdgesFilterSpec(c gospec.Context) {
 			c.Expect(f.CheckEdge(fhead, ftail), IsFalse)
 		})
 		
+		c.Specify("shouldn't be counted", func() {
+			c.Expect(f.EdgesCnt(), Equals, 6)
+		})
+		
 		c.Specify("shouldn't appear in neighbours", func() {
 			c.Expect(CollectVertexes(f.GetNeighbours(VertexId(ftail))), Not(Contains), fhead)
 			c.Expect(CollectVertexes(f.GetNeighbours(VertexId(fhead))), Not(Contains), ftail)
@@ -96,6 +109,10 @@ func MixedGraphConnectionsFilterSpec(c gospec.Context) {
 			c.Expect(f.CheckArc(ftail, fhead), IsFalse)
 		})
 		
+		c.Specify("shouldn't be counted", func() {
+			c.Expect(f.ArcsCnt(), Equals, 6)
+		})
+		
 		c.Specify("shouldn't appear in accessors", func() {
 			c.Expect(CollectVertexes(f.GetAccessors(VertexId(ftail))), Not(Contains), fhead)
 		})
@@ -0,0 +1,323 @@
+package graph
+
+import (
+	"rand"
+	"sort"
+
+	"github.com/StepLg/go-erx/src/erx"
+)
+
+// Maximum flow from source to sink with Edmonds-Karp algorithm
+//
+// Arcs capacities are calculated with capacity function, negative capacity
+// causes panic. Flow is repeatedly increased along the shortest (in arcs count)
+// augmenting path in residual network, until there are no such paths.
+//
+// Returns maximum flow value and flow along each arc: flow[tail][head]. Map
+// contains all arcs from graph, even with zero flow. Self-loop can't be a
+// part of augmenting path, so flow along it is always zero.
+//
+// Flow is a nested map by tail and head instead of map by Connection, because
+// structs can't be map keys in this Go release. Other per-arc results in the
+// package (ParallelArcs, EdgeBetweenness) are nested maps too.
+func MaxFlow(gr DirectedGraphArcsReader, source, sink VertexId, capacity ConnectionWeightFunc) (float64, map[VertexId]map[VertexId]float64) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Calculate maximum flow with Edmonds-Karp algorithm", e)
+			err.AddV("source", source)
+			err.AddV("sink", sink)
+			panic(err)
+		}
+	}()
+	
+	flowValue, capacities, residual := edmondsKarp(gr, source, sink, capacity)
+	
+	flow := make(map[VertexId]map[VertexId]float64)
+	for conn := range gr.ArcsIter() {
+		if _, ok := flow[conn.Tail]; !ok {
+			flow[conn.Tail] = make(map[VertexId]float64)
+		}
+		// for antiparallel arcs residual network contains net flow only, so
+		// all of it is assigned to one of arcs
+		netFlow := capacities[conn.Tail][conn.Head] - residual[conn.Tail][conn.Head]
+		if netFlow>0 && conn.Tail!=conn.Head {
+			flow[conn.Tail][conn.Head] = netFlow
+		} else {
+			flow[conn.Tail][conn.Head] = 0.0
+		}
+	}
+	
+	return flowValue, flow
+}
+
+// Minimum cut between source and sink
+//
+// Maximum flow is calculated with MaxFlow, and then cut consists of arcs from
+// nodes, reachable from source in residual network, to all other nodes.
+//
+// Returns cut arcs and their total capacity, which is equal to maximum flow
+// value.
+func MinCut(gr DirectedGraphArcsReader, source, sink VertexId, capacity ConnectionWeightFunc) ([]Connection, float64) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Calculate minimum cut", e)
+			err.AddV("source", source)
+			err.AddV("sink", sink)
+			panic(err)
+		}
+	}()
+	
+	_, capacities, residual := edmondsKarp(gr, source, sink, capacity)
+	
+	reachable := make(map[VertexId]bool)
+	reachable[source] = true
+	queue := make([]VertexId, 0, 10)
+	queue = append(queue, source)
+	for len(queue)>0 {
+		curNode := queue[0]
+		queue = queue[1:]
+		for nextNode, residualCapacity := range residual[curNode] {
+			if !reachable[nextNode] && residualCapacity>0 {
+				reachable[nextNode] = true
+				queue = append(queue, nextNode)
+			}
+		}
+	}
+	
+	cut := make([]Connection, 0, 10)
+	cutCapacity := 0.0
+	for conn := range gr.ArcsIter() {
+		if reachable[conn.Tail] && !reachable[conn.Head] {
+			cut = append(cut, conn)
+			cutCapacity += capacities[conn.Tail][conn.Head]
+		}
+	}
+	return cut, cutCapacity
+}
+
+// Edmonds-Karp algorithm.
+//
+// Returns maximum flow value, arcs capacities and final residual network.
+func edmondsKarp(gr DirectedGraphArcsReader, source, sink VertexId, capacity ConnectionWeightFunc) (float64, map[VertexId]map[VertexId]float64, map[VertexId]map[VertexId]float64) {
+	if source==sink {
+		panic(erx.NewError("Source and sink are equal."))
+	}
+	
+	capacities, residual := residualNetwork(gr, capacity)
+	
+	flowValue := 0.0
+	for {
+		// breadth-first search of augmenting path
+		prev := make(map[VertexId]VertexId)
+		prev[source] = source
+		queue := make([]VertexId, 0, 10)
+		queue = append(queue, source)
+		for len(queue)>0 {
+			if _, ok := prev[sink]; ok {
+				break
+			}
+			curNode := queue[0]
+			queue = queue[1:]
+			for nextNode, residualCapacity := range residual[curNode] {
+				if _, ok := prev[nextNode]; !ok && residualCapacity>0 {
+					prev[nextNode] = curNode
+					queue = append(queue, nextNode)
+				}
+			}
+		}
+		if _, ok := prev[sink]; !ok {
+			break
+		}
+		
+		pathFlow := -1.0
+		for node:=sink; node!=source; node=prev[node] {
+			if residualCapacity := residual[prev[node]][node]; pathFlow<0 || residualCapacity<pathFlow {
+				pathFlow = residualCapacity
+			}
+		}
+		for node:=sink; node!=source; node=prev[node] {
+			residual[prev[node]][node] -= pathFlow
+			residual[node][prev[node]] += pathFlow
+		}
+		flowValue += pathFlow
+	}
+	
+	return flowValue, capacities, residual
+}
+
+// Build arcs capacities map and initial residual network for flow algorithms.
+//
+// Residual network contains reverse connection with zero capacity for each arc.
+func residualNetwork(gr DirectedGraphArcsReader, capacity ConnectionWeightFunc) (map[VertexId]map[VertexId]float64, map[VertexId]map[VertexId]float64) {
+	capacities := make(map[VertexId]map[VertexId]float64)
+	residual := make(map[VertexId]map[VertexId]float64)
+	touch := func(m map[VertexId]map[VertexId]float64, node VertexId) {
+		if _, ok := m[node]; !ok {
+			m[node] = make(map[VertexId]float64)
+		}
+	}
+	for conn := range gr.ArcsIter() {
+		arcCapacity := capacity(conn.Tail, conn.Head)
+		if arcCapacity < 0 {
+			err := erx.NewError("Negative capacity detected")
+			err.AddV("tail", conn.Tail)
+			err.AddV("head", conn.Head)
+			err.AddV("capacity", arcCapacity)
+			panic(err)
+		}
+		touch(capacities, conn.Tail)
+		capacities[conn.Tail][conn.Head] = arcCapacity
+		touch(residual, conn.Tail)
+		touch(residual, conn.Head)
+		residual[conn.Tail][conn.Head] += arcCapacity
+		if _, ok := residual[conn.Head][conn.Tail]; !ok {
+			residual[conn.Head][conn.Tail] = 0.0
+		}
+	}
+	return capacities, residual
+}
+
+// Global minimum cut of unweighted undirected graph with Karger algorithm
+//
+// Each trial contracts edges in random order until only two super-vertices
+// are left, and edges between them form a cut. Contraction is done with
+// disjoint sets instead of ContractEdge, because parallel edges between
+// super-vertices must be kept. Self-loops never cross a cut.
+//
+// It's Monte Carlo algorithm: single trial finds a given minimum cut with
+// probability at least 2/(n*(n-1)) for graph with n vertexes, so probability
+// to miss it after all trials is at most (1 - 2/(n*(n-1)))^trials, which is
+// about exp(-2*trials/n^2). n^2*ln(n)/2 trials give failure probability 1/n.
+// Nil rng means package-level default generator.
+//
+// Vertexes are taken from edges. Returns the smallest found cut edges and
+// their count. Disconnected graph has empty cut, and graph with less than two
+// vertexes has no cut at all, so (nil, 0) is returned.
+func KargerMinCut(gr UndirectedGraphEdgesReader, rng *rand.Rand, trials int) ([]Connection, int) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Search minimum cut with Karger algorithm", e)
+			err.AddV("trials", trials)
+			panic(err)
+		}
+	}()
+	
+	if trials<=0 {
+		err := erx.NewError("Trials count must be positive.")
+		panic(err)
+	}
+	if rng==nil {
+		rng = defaultGeneratorRand
+	}
+	
+	edges := make([]Connection, 0, gr.EdgesCnt())
+	nodesSet := make(map[VertexId]bool)
+	for conn := range gr.EdgesIter() {
+		edges = append(edges, conn)
+		nodesSet[conn.Tail] = true
+		nodesSet[conn.Head] = true
+	}
+	// fixed edges order makes result reproducible with seeded rng
+	sort.Sort(connectionsByNodes(edges))
+	nodesCnt := len(nodesSet)
+	if nodesCnt<2 {
+		return nil, 0
+	}
+	
+	var bestCut []Connection
+	bestCutSize := -1
+	for trial:=0; trial<trials; trial++ {
+		sets := newVertexesDisjointSets()
+		setsCnt := nodesCnt
+		for _, pos := range rng.Perm(len(edges)) {
+			if setsCnt==2 {
+				break
+			}
+			if sets.Union(edges[pos].Tail, edges[pos].Head) {
+				setsCnt--
+			}
+		}
+		
+		cut := make([]Connection, 0, 10)
+		if setsCnt==2 {
+			for _, conn := range edges {
+				if sets.Find(conn.Tail)!=sets.Find(conn.Head) {
+					cut = append(cut, conn)
+				}
+			}
+		}
+		if bestCutSize==-1 || len(cut)<bestCutSize {
+			bestCut = cut
+			bestCutSize = len(cut)
+		}
+		if bestCutSize==0 {
+			break
+		}
+	}
+	
+	sort.Sort(connectionsByNodes(bestCut))
+	return bestCut, bestCutSize
+}
+
+// Maximum matching in bipartite graph
+//
+// leftSet contains vertexes of one part of graph (with true value), all other
+// vertexes are in another part. Every edge must connect vertexes from
+// different parts, otherwise function panics.
+//
+// Matching is built with augmenting paths search from each left vertex
+// (Kuhn algorithm). Returns partners for all matched vertexes: both
+// result[left]==right and result[right]==left.
+func MaximumBipartiteMatching(gr UndirectedGraphEdgesReader, leftSet map[VertexId]bool) map[VertexId]VertexId {
+	defer func() {
+		if e:=recover(); e!=nil {
+			panic(erx.NewSequent("Search maximum bipartite matching", e))
+		}
+	}()
+	
+	// neighbours of left vertexes
+	neighbours := make(map[VertexId][]VertexId)
+	for conn := range gr.EdgesIter() {
+		left, right := conn.Tail, conn.Head
+		if !leftSet[left] {
+			left, right = right, left
+		}
+		if !leftSet[left] || leftSet[right] {
+			err := erx.NewError("Edge doesn't connect left and right vertexes.")
+			err.AddV("edge", conn)
+			panic(err)
+		}
+		neighbours[left] = append(neighbours[left], right)
+	}
+	leftNodes := make(Vertexes, 0, len(neighbours))
+	for node, _ := range neighbours {
+		leftNodes = append(leftNodes, node)
+	}
+	sort.Sort(leftNodes)
+	
+	matching := make(map[VertexId]VertexId)
+	for _, node := range leftNodes {
+		visited := make(map[VertexId]bool)
+		bipartiteMatchingAugment(node, neighbours, matching, visited)
+	}
+	return matching
+}
+
+// Search augmenting path from left vertex and alternate matching along it.
+//
+// visited contains right vertexes, which are already checked in this search.
+func bipartiteMatchingAugment(left VertexId, neighbours map[VertexId][]VertexId, matching map[VertexId]VertexId, visited map[VertexId]bool) bool {
+	for _, right := range neighbours[left] {
+		if visited[right] {
+			continue
+		}
+		visited[right] = true
+		partner, isMatched := matching[right]
+		if !isMatched || bipartiteMatchingAugment(partner, neighbours, matching, visited) {
+			matching[left] = right
+			matching[right] = left
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package graph
+
+import (
+	"rand"
+	"testing"
+	"github.com/orfjackal/gospec/src/gospec"
+	. "github.com/orfjackal/gospec/src/gospec"
+)
+
+func MaxFlowSpec(c gospec.Context) {
+	gr, capacity := generateFlowNetwork1()
+	
+	c.Specify("Cormen flow network", func() {
+		flowValue, flow := MaxFlow(gr, 1, 6, capacity)
+		c.Expect(flowValue, Equals, 23.0)
+		
+		// capacities constraints
+		for conn := range gr.ArcsIter() {
+			arcFlow := flow[conn.Tail][conn.Head]
+			c.Expect(arcFlow>=0 && arcFlow<=capacity(conn.Tail, conn.Head), IsTrue)
+		}
+		// flow conservation
+		balance := make(map[VertexId]float64)
+		for conn := range gr.ArcsIter() {
+			balance[conn.Tail] -= flow[conn.Tail][conn.Head]
+			balance[conn.Head] += flow[conn.Tail][conn.Head]
+		}
+		c.Expect(balance[1], Equals, -23.0)
+		c.Expect(balance[6], Equals, 23.0)
+		for node:=VertexId(2); node<=5; node++ {
+			c.Expect(balance[node], Equals, 0.0)
+		}
+	})
+	
+	c.Specify("No path from source to sink", func() {
+		flowValue, flow := MaxFlow(gr, 6, 1, capacity)
+		c.Expect(flowValue, Equals, 0.0)
+		c.Expect(flow[1][2], Equals, 0.0)
+	})
+	
+	c.Specify("Negative capacity", func() {
+		defer func() {
+			c.Expect(recover(), Not(IsNil))
+		}()
+		MaxFlow(gr, 1, 6, func(tail, head VertexId) float64 {
+			return -1.0
+		})
+	})
+}
+
+func MinCutSpec(c gospec.Context) {
+	gr, capacity := generateFlowNetwork1()
+	
+	c.Specify("Cut capacity equals maximum flow", func() {
+		cut, cutCapacity := MinCut(gr, 1, 6, capacity)
+		flowValue, _ := MaxFlow(gr, 1, 6, capacity)
+		c.Expect(cutCapacity, Equals, flowValue)
+		c.Expect(len(cut), Equals, 3)
+		
+		sum := 0.0
+		for _, conn := range cut {
+			sum += capacity(conn.Tail, conn.Head)
+		}
+		c.Expect(sum, Equals, 23.0)
+	})
+	
+	c.Specify("Cut separates source from sink", func() {
+		cut, _ := MinCut(gr, 1, 6, capacity)
+		filteredGr := NewDirectedGraphArcsFilter(gr, cut)
+		_, ok := CheckPathDijkstra(NewDgraphOutNeighboursExtractor(filteredGr), 1, 6, nil, SimpleWeightFunc)
+		c.Expect(ok, IsFalse)
+	})
+}
+
+func MaximumBipartiteMatchingSpec(c gospec.Context) {
+	leftSet := map[VertexId]bool{1:true, 2:true, 3:true}
+	
+	c.Specify("Perfect matching in 3x3 graph", func() {
+		gr := NewUndirectedMap()
+		// greedy matching 1-4, 2-5 can't be extended without augmenting path
+		gr.AddEdge(1, 4)
+		gr.AddEdge(1, 5)
+		gr.AddEdge(2, 4)
+		gr.AddEdge(3, 5)
+		gr.AddEdge(3, 6)
+		gr.AddEdge(2, 6)
+		matching := MaximumBipartiteMatching(gr, leftSet)
+		c.Expect(len(matching), Equals, 6)
+		for left, _ := range leftSet {
+			right := matching[left]
+			c.Expect(gr.CheckEdge(left, right), IsTrue)
+			c.Expect(matching[right], Equals, left)
+		}
+	})
+	
+	c.Specify("Augmenting path is used", func() {
+		gr := NewUndirectedMap()
+		gr.AddEdge(1, 4)
+		gr.AddEdge(1, 5)
+		gr.AddEdge(2, 4)
+		gr.AddEdge(3, 4)
+		matching := MaximumBipartiteMatching(gr, leftSet)
+		c.Expect(len(matching), Equals, 4)
+		c.Expect(matching[1], Equals, VertexId(5))
+	})
+	
+	c.Specify("Edge inside one part", func() {
+		gr := NewUndirectedMap()
+		gr.AddEdge(1, 4)
+		gr.AddEdge(1, 2)
+		defer func() {
+			c.Expect(recover(), Not(IsNil))
+		}()
+		MaximumBipartiteMatching(gr, leftSet)
+	})
+}
+
+func KargerMinCutSpec(c gospec.Context) {
+	rng := rand.New(rand.NewSource(5))
+	
+	c.Specify("Two cliques connected with single edge", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-4-1-3")
+		ReadUgraphLine(gr, "2-4")
+		ReadUgraphLine(gr, "5-6-7-8-5-7")
+		ReadUgraphLine(gr, "6-8")
+		ReadUgraphLine(gr, "4-5")
+		cut, cutSize := KargerMinCut(gr, rng, 100)
+		c.Expect(cutSize, Equals, 1)
+		c.Expect(len(cut), Equals, 1)
+		c.Expect(cut[0].String(), Equals, "4->5")
+	})
+	
+	c.Specify("Cycle", func() {
+		_, cutSize := KargerMinCut(CycleGraph(6), rng, 50)
+		c.Expect(cutSize, Equals, 2)
+	})
+	
+	c.Specify("Disconnected graph", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3")
+		ReadUgraphLine(gr, "4-5")
+		cut, cutSize := KargerMinCut(gr, rng, 10)
+		c.Expect(cutSize, Equals, 0)
+		c.Expect(len(cut), Equals, 0)
+	})
+}
+
+func TestFlow(t *testing.T) {
+	r := gospec.NewRunner()
+	r.AddSpec(MaxFlowSpec)
+	r.AddSpec(MinCutSpec)
+	r.AddSpec(KargerMinCutSpec)
+	r.AddSpec(MaximumBipartiteMatchingSpec)
+	gospec.MainGoTest(r, t)
+}
@@ -0,0 +1,228 @@
+package graph
+
+import (
+	"math"
+	"rand"
+
+	"github.com/StepLg/go-erx/src/erx"
+)
+
+// Random number generator, used by generators if nil rng is passed.
+var defaultGeneratorRand = rand.New(rand.NewSource(1))
+
+// Random directed graph in Erdos-Renyi G(n, p) model
+//
+// Graph contains n vertexes with ids from 0 to n-1, and each of n*(n-1)
+// possible arcs (without loops) is added independently with probability p.
+// Use seeded rng to get reproducible graphs, nil rng means package-level
+// default generator.
+func GenerateRandomDirected(n int, p float64, rng *rand.Rand) DirectedGraph {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Generate random directed graph", e)
+			err.AddV("n", n)
+			err.AddV("p", p)
+			panic(err)
+		}
+	}()
+	
+	checkGeneratorParams(n, p)
+	if rng==nil {
+		rng = defaultGeneratorRand
+	}
+	
+	gr := NewDirectedMap()
+	for i:=0; i<n; i++ {
+		gr.AddNode(VertexId(i))
+	}
+	for i:=0; i<n; i++ {
+		for j:=0; j<n; j++ {
+			if i!=j && rng.Float64() < p {
+				gr.AddArc(VertexId(i), VertexId(j))
+			}
+		}
+	}
+	return gr
+}
+
+// Random weighted directed graph in Erdos-Renyi G(n, p) model
+//
+// Works like GenerateRandomDirected, but also calls weightFunc once for each
+// added arc and saves result. Returned weight function gives saved weights,
+// so repeated calls for the same arc always return the same value. Weight of
+// absent arc is math.MaxFloat64. Arcs are generated in fixed order, so if
+// weightFunc uses the same seeded rng, then weights are reproducible too.
+func GenerateRandomWeightedDirected(n int, p float64, weightFunc ConnectionWeightFunc, rng *rand.Rand) (DirectedGraph, ConnectionWeightFunc) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Generate random weighted directed graph", e)
+			err.AddV("n", n)
+			err.AddV("p", p)
+			panic(err)
+		}
+	}()
+	
+	checkGeneratorParams(n, p)
+	if rng==nil {
+		rng = defaultGeneratorRand
+	}
+	
+	gr := NewDirectedMap()
+	weights := make(map[VertexId]map[VertexId]float64, n)
+	for i:=0; i<n; i++ {
+		gr.AddNode(VertexId(i))
+		weights[VertexId(i)] = make(map[VertexId]float64)
+	}
+	for i:=0; i<n; i++ {
+		for j:=0; j<n; j++ {
+			if i!=j && rng.Float64() < p {
+				tail, head := VertexId(i), VertexId(j)
+				gr.AddArc(tail, head)
+				weights[tail][head] = weightFunc(tail, head)
+			}
+		}
+	}
+	
+	savedWeightFunc := func(tail, head VertexId) float64 {
+		if weight, ok := weights[tail][head]; ok {
+			return weight
+		}
+		return math.MaxFloat64
+	}
+	return gr, savedWeightFunc
+}
+
+// Random undirected graph in Erdos-Renyi G(n, p) model
+//
+// Works like GenerateRandomDirected, but each of n*(n-1)/2 possible edges is
+// added with probability p.
+func GenerateRandomUndirected(n int, p float64, rng *rand.Rand) UndirectedGraph {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Generate random undirected graph", e)
+			err.AddV("n", n)
+			err.AddV("p", p)
+			panic(err)
+		}
+	}()
+	
+	checkGeneratorParams(n, p)
+	if rng==nil {
+		rng = defaultGeneratorRand
+	}
+	
+	gr := NewUndirectedMap()
+	for i:=0; i<n; i++ {
+		gr.AddNode(VertexId(i))
+	}
+	for i:=0; i<n; i++ {
+		for j:=i+1; j<n; j++ {
+			if rng.Float64() < p {
+				gr.AddEdge(VertexId(i), VertexId(j))
+			}
+		}
+	}
+	return gr
+}
+
+// Complete undirected graph
+//
+// Graph contains n vertexes with ids from 0 to n-1, and every pair of them is
+// connected with edge.
+func CompleteGraph(n int) UndirectedGraph {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Generate complete graph", e)
+			err.AddV("n", n)
+			panic(err)
+		}
+	}()
+	
+	checkGeneratorParams(n, 1.0)
+	gr := NewUndirectedMap()
+	for i:=0; i<n; i++ {
+		gr.AddNode(VertexId(i))
+	}
+	for i:=0; i<n; i++ {
+		for j:=i+1; j<n; j++ {
+			gr.AddEdge(VertexId(i), VertexId(j))
+		}
+	}
+	return gr
+}
+
+// Undirected cycle graph
+//
+// Graph contains n vertexes with ids from 0 to n-1, each vertex i is connected
+// with i+1, and vertex n-1 is connected with 0. Cycle requires at least 3
+// vertexes, so for n<3 result is just a path: single vertex or single edge.
+func CycleGraph(n int) UndirectedGraph {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Generate cycle graph", e)
+			err.AddV("n", n)
+			panic(err)
+		}
+	}()
+	
+	checkGeneratorParams(n, 1.0)
+	gr := NewUndirectedMap()
+	for i:=0; i<n; i++ {
+		gr.AddNode(VertexId(i))
+	}
+	for i:=0; i+1<n; i++ {
+		gr.AddEdge(VertexId(i), VertexId(i+1))
+	}
+	if n>=3 {
+		gr.AddEdge(VertexId(n-1), VertexId(0))
+	}
+	return gr
+}
+
+// Undirected grid graph
+//
+// Graph contains rows*cols vertexes, vertex in row r and column c has id
+// r*cols + c. Each vertex is connected with it's right (same row, next column)
+// and bottom (next row, same column) neighbours.
+func GridGraph(rows, cols int) UndirectedGraph {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Generate grid graph", e)
+			err.AddV("rows", rows)
+			err.AddV("cols", cols)
+			panic(err)
+		}
+	}()
+	
+	checkGeneratorParams(rows, 1.0)
+	checkGeneratorParams(cols, 1.0)
+	gr := NewUndirectedMap()
+	for i:=0; i<rows*cols; i++ {
+		gr.AddNode(VertexId(i))
+	}
+	for r:=0; r<rows; r++ {
+		for c:=0; c<cols; c++ {
+			node := VertexId(r*cols + c)
+			if c+1<cols {
+				gr.AddEdge(node, node+1)
+			}
+			if r+1<rows {
+				gr.AddEdge(node, node+VertexId(cols))
+			}
+		}
+	}
+	return gr
+}
+
+func checkGeneratorParams(n int, p float64) {
+	if n<0 {
+		err := erx.NewError("Negative vertexes count.")
+		err.AddV("n", n)
+		panic(err)
+	}
+	if p<0 || p>1 {
+		err := erx.NewError("Probability must be in [0, 1] range.")
+		err.AddV("p", p)
+		panic(err)
+	}
+}
@@ -0,0 +1,134 @@
+package graph
+
+import (
+	"math"
+	"rand"
+	"testing"
+	"github.com/orfjackal/gospec/src/gospec"
+	. "github.com/orfjackal/gospec/src/gospec"
+)
+
+func GenerateRandomDirectedSpec(c gospec.Context) {
+	c.Specify("Extreme probabilities", func() {
+		gr := GenerateRandomDirected(5, 0.0, nil)
+		c.Expect(gr.Order(), Equals, 5)
+		c.Expect(gr.ArcsCnt(), Equals, 0)
+		gr = GenerateRandomDirected(5, 1.0, nil)
+		c.Expect(gr.ArcsCnt(), Equals, 5*4)
+		c.Expect(gr.CheckArc(4, 0), IsTrue)
+		c.Expect(gr.CheckArc(2, 2), IsFalse)
+	})
+	
+	c.Specify("Same seed gives same graph", func() {
+		gr1 := GenerateRandomDirected(20, 0.3, rand.New(rand.NewSource(7)))
+		gr2 := GenerateRandomDirected(20, 0.3, rand.New(rand.NewSource(7)))
+		c.Expect(DirectedGraphsEquals(gr1, gr2), IsTrue)
+	})
+}
+
+func GenerateRandomUndirectedSpec(c gospec.Context) {
+	c.Specify("Extreme probabilities", func() {
+		gr := GenerateRandomUndirected(5, 0.0, nil)
+		c.Expect(gr.Order(), Equals, 5)
+		c.Expect(gr.EdgesCnt(), Equals, 0)
+		gr = GenerateRandomUndirected(5, 1.0, nil)
+		c.Expect(gr.EdgesCnt(), Equals, 5*4/2)
+	})
+	
+	c.Specify("Same seed gives same graph", func() {
+		gr1 := GenerateRandomUndirected(20, 0.3, rand.New(rand.NewSource(7)))
+		gr2 := GenerateRandomUndirected(20, 0.3, rand.New(rand.NewSource(7)))
+		c.Expect(UndirectedGraphsEquals(gr1, gr2), IsTrue)
+	})
+}
+
+func GenerateRandomWeightedDirectedSpec(c gospec.Context) {
+	rng := rand.New(rand.NewSource(3))
+	calls := 0
+	randomWeight := func(tail, head VertexId) float64 {
+		calls++
+		return rng.Float64()
+	}
+	gr, weightFunc := GenerateRandomWeightedDirected(10, 0.5, randomWeight, rng)
+	
+	c.Specify("Weight function is called once per arc", func() {
+		c.Expect(calls, Equals, gr.ArcsCnt())
+	})
+	
+	c.Specify("Weights are stable", func() {
+		for conn := range gr.ArcsIter() {
+			weight := weightFunc(conn.Tail, conn.Head)
+			c.Expect(weight >= 0 && weight < 1, IsTrue)
+			c.Expect(weightFunc(conn.Tail, conn.Head), Equals, weight)
+		}
+		c.Expect(calls, Equals, gr.ArcsCnt())
+	})
+	
+	c.Specify("Absent arc", func() {
+		c.Expect(weightFunc(0, 0), Equals, math.MaxFloat64)
+	})
+	
+	c.Specify("Usable in Dijkstra", func() {
+		dist := FloydWarshall(gr, weightFunc)
+		for to, weight := range dist[0] {
+			pathWeight, ok := CheckPathDijkstra(NewDgraphOutNeighboursExtractor(gr), 0, to, nil, weightFunc)
+			c.Expect(ok, Equals, weight!=math.MaxFloat64)
+			if ok {
+				c.Expect(math.Fabs(pathWeight - weight) < 1e-9, IsTrue)
+			}
+		}
+	})
+}
+
+func CompleteGraphSpec(c gospec.Context) {
+	gr := CompleteGraph(5)
+	c.Expect(gr.Order(), Equals, 5)
+	c.Expect(gr.EdgesCnt(), Equals, 10)
+	c.Expect(gr.CheckEdge(0, 4), IsTrue)
+	c.Expect(Degree(gr, 2), Equals, 4)
+	c.Expect(CompleteGraph(0).Order(), Equals, 0)
+}
+
+func CycleGraphSpec(c gospec.Context) {
+	c.Specify("Cycle", func() {
+		gr := CycleGraph(5)
+		c.Expect(gr.Order(), Equals, 5)
+		c.Expect(gr.EdgesCnt(), Equals, 5)
+		c.Expect(gr.CheckEdge(4, 0), IsTrue)
+		c.Expect(gr.CheckEdge(1, 2), IsTrue)
+		c.Expect(gr.CheckEdge(0, 2), IsFalse)
+	})
+	
+	c.Specify("Too small for cycle", func() {
+		c.Expect(CycleGraph(1).EdgesCnt(), Equals, 0)
+		c.Expect(CycleGraph(2).EdgesCnt(), Equals, 1)
+	})
+	
+	c.Specify("Negative vertexes count", func() {
+		defer func() {
+			c.Expect(recover(), Not(IsNil))
+		}()
+		CycleGraph(-1)
+	})
+}
+
+func GridGraphSpec(c gospec.Context) {
+	gr := GridGraph(3, 4)
+	c.Expect(gr.Order(), Equals, 12)
+	c.Expect(gr.EdgesCnt(), Equals, 3*3 + 2*4)
+	// vertex 5 is in row 1, column 1
+	c.Expect(CollectVertexes(gr.GetNeighbours(5)), ContainsExactly, Values(VertexId(1), VertexId(4), VertexId(6), VertexId(9)))
+	c.Expect(CollectVertexes(gr.GetNeighbours(11)), ContainsExactly, Values(VertexId(7), VertexId(10)))
+	c.Expect(gr.CheckEdge(3, 4), IsFalse)
+}
+
+func TestGenerators(t *testing.T) {
+	r := gospec.NewRunner()
+	r.AddSpec(GenerateRandomDirectedSpec)
+	r.AddSpec(GenerateRandomUndirectedSpec)
+	r.AddSpec(GenerateRandomWeightedDirectedSpec)
+	r.AddSpec(CompleteGraphSpec)
+	r.AddSpec(CycleGraphSpec)
+	r.AddSpec(GridGraphSpec)
+	gospec.MainGoTest(r, t)
+}
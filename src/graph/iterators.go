@@ -2,6 +2,7 @@ package graph
 
 import (
 	. "exp/iterable"
+	"sort"
 
 	"github.com/StepLg/go-erx/src/erx"
 )
@@ -103,6 +104,74 @@ func CollectVertexes(iter VertexesIterable) []VertexId {
 	return res[0:i]
 }
 
+// Collect all vertexes from iterator to slice, sorted by id.
+//
+// Graphs iterators order may differ from run to run, so algorithms, which
+// must give reproducible results, should iterate over sorted vertexes.
+func SortedVertexes(iter VertexesIterable) []VertexId {
+	nodes := CollectVertexes(iter)
+	sort.Sort(Vertexes(nodes))
+	return nodes
+}
+
+// Collect all graph arcs to slice, sorted by tail and then by head.
+func SortedArcs(gr DirectedGraphArcsReader) []Connection {
+	arcs := make([]Connection, 0, gr.ArcsCnt())
+	for conn := range gr.ArcsIter() {
+		arcs = append(arcs, conn)
+	}
+	sort.Sort(connectionsByNodes(arcs))
+	return arcs
+}
+
+// Stream graph arcs, which satisfy predicate
+//
+// Channel is closed after the last matching arc, or as soon as anything is
+// received from done channel (or it's closed), so reading can be stopped
+// before the end without blocking goroutine forever. Graph arcs iterator
+// can't be stopped, so after that it's read to the end without sending
+// anything. nil done channel means that streaming is never stopped.
+func FilterArcs(gr DirectedGraphArcsReader, pred func(Connection) bool, done <-chan bool) <-chan Connection {
+	ch := make(chan Connection)
+	go func() {
+		stopped := false
+		for conn := range gr.ArcsIter() {
+			if stopped || !pred(conn) {
+				continue
+			}
+			select {
+				case ch <- conn:
+				case <-done:
+					stopped = true
+			}
+		}
+		close(ch)
+	}()
+	return ch
+}
+
+// Stream vertexes, which satisfy predicate
+//
+// Works just like FilterArcs, but for any vertexes iterator.
+func FilterVertexes(iter VertexesIterable, pred func(VertexId) bool, done <-chan bool) <-chan VertexId {
+	ch := make(chan VertexId)
+	go func() {
+		stopped := false
+		for node := range iter.VertexesIter() {
+			if stopped || !pred(node) {
+				continue
+			}
+			select {
+				case ch <- node:
+				case <-done:
+					stopped = true
+			}
+		}
+		close(ch)
+	}()
+	return ch
+}
+
 // Build directed graph from connecection iterator with order function
 //
 // For all connections from iterator check isCorrectOrder function 
@@ -3,6 +3,7 @@ package graph
 import (
 	"fmt"
 	"os"
+	"sort"
 	"testing"
 	"github.com/orfjackal/gospec/src/gospec"
 	. "github.com/orfjackal/gospec/src/gospec"
@@ -81,8 +82,77 @@ func ArrowsIteratorSpec(c gospec.Context) {
 	})
 }
 
+func SortedIteratorsSpec(c gospec.Context) {
+	gr := NewDirectedMap()
+	ReadDgraphLine(gr, "5>3>1")
+	ReadDgraphLine(gr, "3>2")
+	ReadDgraphLine(gr, "1>5")
+	gr.AddNode(4)
+	
+	c.Specify("Sorted vertexes", func() {
+		c.Expect(SortedVertexes(gr), ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(3), VertexId(4), VertexId(5)))
+		c.Expect(SortedVertexes(gr.GetAccessors(3)), ContainsInOrder, Values(VertexId(1), VertexId(2)))
+	})
+	
+	c.Specify("Sorted arcs", func() {
+		arcs := SortedArcs(gr)
+		c.Expect(len(arcs), Equals, 4)
+		c.Expect(arcs[0].String(), Equals, "1->5")
+		c.Expect(arcs[1].String(), Equals, "3->1")
+		c.Expect(arcs[2].String(), Equals, "3->2")
+		c.Expect(arcs[3].String(), Equals, "5->3")
+	})
+}
+
+func FilterIteratorsSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	
+	c.Specify("Only matching arcs", func() {
+		arcs := make([]Connection, 0, 10)
+		for conn := range FilterArcs(gr, func(conn Connection) bool { return conn.Tail==2 }, nil) {
+			arcs = append(arcs, conn)
+		}
+		sort.Sort(connectionsByNodes(arcs))
+		c.Expect(len(arcs), Equals, 3)
+		c.Expect(arcs[0].String(), Equals, "2->3")
+		c.Expect(arcs[1].String(), Equals, "2->4")
+		c.Expect(arcs[2].String(), Equals, "2->6")
+	})
+	
+	c.Specify("Only matching vertexes", func() {
+		nodes := make([]VertexId, 0, 10)
+		for node := range FilterVertexes(gr, func(node VertexId) bool { return node%2==0 }, nil) {
+			nodes = append(nodes, node)
+		}
+		c.Expect(nodes, ContainsExactly, Values(VertexId(2), VertexId(4), VertexId(6)))
+	})
+	
+	c.Specify("Streaming is stopped with done channel", func() {
+		done := make(chan bool)
+		arcs := FilterArcs(gr, func(conn Connection) bool { return true }, done)
+		<-arcs
+		close(done)
+		cnt := 0
+		for _ = range arcs {
+			cnt++
+		}
+		c.Expect(cnt < gr.ArcsCnt(), IsTrue)
+		
+		nodesDone := make(chan bool)
+		nodes := FilterVertexes(gr, func(node VertexId) bool { return true }, nodesDone)
+		nodesDone <- true
+		cnt = 0
+		for _ = range nodes {
+			cnt++
+		}
+		c.Expect(cnt < gr.Order(), IsTrue)
+	})
+}
+
 func TestArrowsIteratorSpec(t *testing.T) {
 	r := gospec.NewRunner()
 	r.AddSpec(ArrowsIteratorSpec)
+	r.AddSpec(SortedIteratorsSpec)
+	r.AddSpec(FilterIteratorsSpec)
 	gospec.MainGoTest(r, t)
 }
@@ -0,0 +1,570 @@
+package graph
+
+import (
+	"math"
+
+	"github.com/StepLg/go-erx/src/erx"
+)
+
+// Eccentricity of node: maximal shortest path weight from node to any other
+// node, reachable from it.
+//
+// Unreachable nodes (infinite distances) are ignored, so eccentricity of node
+// without accessors is 0. Pass SimpleWeightFunc to count path lengths in arcs.
+//
+// Shortest paths are calculated with Bellman-Ford algorithm, so negative
+// weights are allowed, but negative cycles cause panic.
+func Eccentricity(gr DirectedGraphReader, node VertexId, weightFunc ConnectionWeightFunc) float64 {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Calculate node eccentricity", e)
+			err.AddV("node", node)
+			panic(err)
+		}
+	}()
+	
+	dist, _, ok := BellmanFordSingleSourcePaths(gr, node, weightFunc)
+	if !ok {
+		panic(erx.NewError("Negative cycle detected."))
+	}
+	return maxFiniteDistance(dist)
+}
+
+// Graph diameter: maximal eccentricity over all nodes.
+//
+// Only reachable pairs of nodes are taken into account, infinite distances
+// between disconnected nodes are ignored. Diameter of empty graph is 0.
+//
+// Built on top of FloydWarshall, so negative cycles cause panic.
+func Diameter(gr DirectedGraphReader, weightFunc ConnectionWeightFunc) float64 {
+	defer func() {
+		if e:=recover(); e!=nil {
+			panic(erx.NewSequent("Calculate graph diameter", e))
+		}
+	}()
+	
+	diameter := 0.0
+	for _, eccentricity := range eccentricities(gr, weightFunc) {
+		if eccentricity>diameter {
+			diameter = eccentricity
+		}
+	}
+	return diameter
+}
+
+// Diameter of unweighted tree with two breadth-first searches
+//
+// The farthest vertex u from any vertex is an end of some longest path, so
+// the farthest vertex v from u gives diameter path between u and v. Takes
+// O(V+E) time, instead of all-pairs shortest paths in Diameter.
+//
+// Graph must be a tree (see IsTree), otherwise function panics: for graphs
+// with cycles the farthest vertex isn't necessarily an end of diameter.
+//
+// Returns diameter length in edges and diameter path from u to v. Diameter of
+// single vertex tree is 0 with this vertex as path.
+func TreeDiameter(gr UndirectedGraphEdgesReader) (int, []VertexId) {
+	if !IsTree(gr) {
+		panic(erx.NewError("Graph isn't a tree."))
+	}
+	
+	start := sortedUndirectedGraphVertexes(gr)[0]
+	u, _, _ := treeFarthestNode(gr, start)
+	v, dist, prev := treeFarthestNode(gr, u)
+	return dist[v], pathFromPredecessors(prev, u, v)
+}
+
+// Graph radius: minimal eccentricity over all nodes.
+//
+// Only reachable pairs of nodes are taken into account, infinite distances
+// between disconnected nodes are ignored. Thus node without accessors has
+// eccentricity 0, and radius of graph with such node is 0 too. Radius of empty
+// graph is 0.
+//
+// Built on top of FloydWarshall, so negative cycles cause panic.
+func Radius(gr DirectedGraphReader, weightFunc ConnectionWeightFunc) float64 {
+	defer func() {
+		if e:=recover(); e!=nil {
+			panic(erx.NewSequent("Calculate graph radius", e))
+		}
+	}()
+	
+	radius := math.MaxFloat64
+	for _, eccentricity := range eccentricities(gr, weightFunc) {
+		if eccentricity<radius {
+			radius = eccentricity
+		}
+	}
+	if radius==math.MaxFloat64 {
+		return 0.0
+	}
+	return radius
+}
+
+// PageRank of directed graph nodes with power iteration.
+//
+// Random surfer follows one of current node accessors with damping
+// probability, and jumps to random node otherwise. Dangling nodes (without
+// accessors) distribute their rank uniformly over all nodes. Iterations stop
+// after given iterations count or earlier, when L1 distance between successive
+// ranks vectors is less than tolerance. Ranks sum is 1.
+//
+// Damping must be in [0, 1] range, otherwise function panics.
+func PageRank(gr DirectedGraphReader, damping float64, iterations int, tolerance float64) map[VertexId]float64 {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Calculate PageRank", e)
+			err.AddV("damping", damping)
+			err.AddV("iterations", iterations)
+			panic(err)
+		}
+	}()
+	
+	if damping<0 || damping>1 {
+		panic(erx.NewError("Damping factor out of [0, 1] range."))
+	}
+	
+	nodes := CollectVertexes(gr)
+	rank := make(map[VertexId]float64, len(nodes))
+	if len(nodes)==0 {
+		return rank
+	}
+	nodesCnt := float64(len(nodes))
+	accessors := make(map[VertexId][]VertexId, len(nodes))
+	for _, node := range nodes {
+		accessors[node] = CollectVertexes(gr.GetAccessors(node))
+		rank[node] = 1.0 / nodesCnt
+	}
+	
+	for i:=0; i<iterations; i++ {
+		danglingRank := 0.0
+		for _, node := range nodes {
+			if len(accessors[node])==0 {
+				danglingRank += rank[node]
+			}
+		}
+		base := (1.0 - damping + damping * danglingRank) / nodesCnt
+		nextRank := make(map[VertexId]float64, len(nodes))
+		for _, node := range nodes {
+			nextRank[node] += base
+			if nodeAccessors := accessors[node]; len(nodeAccessors)>0 {
+				share := damping * rank[node] / float64(len(nodeAccessors))
+				for _, nextNode := range nodeAccessors {
+					nextRank[nextNode] += share
+				}
+			}
+		}
+		
+		change := 0.0
+		for _, node := range nodes {
+			change += math.Fabs(nextRank[node] - rank[node])
+		}
+		rank = nextRank
+		if change<tolerance {
+			break
+		}
+	}
+	
+	return rank
+}
+
+// Betweenness centrality of directed graph nodes with Brandes algorithm.
+//
+// Node centrality is a sum over all pairs of other nodes (s, t) of fraction of
+// shortest paths from s to t, which pass through the node. Scores aren't
+// normalized.
+//
+// Shortest paths are searched with Dijkstra algorithm, so negative weights
+// cause panic. If weightFunc is nil, then graph is considered unweighted and
+// breadth-first search is used instead.
+func BetweennessCentrality(gr DirectedGraphReader, weightFunc ConnectionWeightFunc) map[VertexId]float64 {
+	defer func() {
+		if e:=recover(); e!=nil {
+			panic(erx.NewSequent("Calculate betweenness centrality", e))
+		}
+	}()
+	
+	nodes := CollectVertexes(gr)
+	accessors := make(map[VertexId][]VertexId, len(nodes))
+	centrality := make(map[VertexId]float64, len(nodes))
+	for _, node := range nodes {
+		accessors[node] = CollectVertexes(gr.GetAccessors(node))
+		centrality[node] = 0.0
+	}
+	
+	// passes for different sources are independent, so they can be run
+	// concurrently with results summed up at the end
+	for _, source := range nodes {
+		for node, dependency := range brandesSourceDependencies(accessors, source, weightFunc) {
+			centrality[node] += dependency
+		}
+	}
+	return centrality
+}
+
+// Closeness centrality of directed graph nodes.
+//
+// Node closeness is count of nodes, reachable from it, divided by sum of
+// shortest paths weights to them: reciprocal of average distance to reachable
+// nodes. So nodes in different components are comparable, but only reachable
+// nodes are taken into account (closeness isn't multiplied by fraction of
+// reachable nodes in graph). Node, which reaches nobody, has 0 closeness.
+//
+// Shortest paths are calculated with Bellman-Ford algorithm, so negative
+// cycles cause panic.
+func ClosenessCentrality(gr DirectedGraphReader, weightFunc ConnectionWeightFunc) map[VertexId]float64 {
+	defer func() {
+		if e:=recover(); e!=nil {
+			panic(erx.NewSequent("Calculate closeness centrality", e))
+		}
+	}()
+	
+	closeness := make(map[VertexId]float64)
+	for _, source := range CollectVertexes(gr) {
+		dist, _, ok := BellmanFordSingleSourcePaths(gr, source, weightFunc)
+		if !ok {
+			err := erx.NewError("Negative cycle detected.")
+			err.AddV("source", source)
+			panic(err)
+		}
+		reachableCnt := 0
+		sumDist := 0.0
+		for node, weight := range dist {
+			if node!=source && weight!=math.MaxFloat64 {
+				reachableCnt++
+				sumDist += weight
+			}
+		}
+		if reachableCnt==0 {
+			closeness[source] = 0.0
+		} else {
+			closeness[source] = float64(reachableCnt) / sumDist
+		}
+	}
+	return closeness
+}
+
+// Harmonic centrality of directed graph nodes.
+//
+// Node centrality is a sum of reciprocals of shortest paths weights to all
+// other nodes, reachable from it. Unreachable nodes contribute 0 instead of
+// infinite distance, so unlike ClosenessCentrality scores are comparable for
+// disconnected graphs too. Scores aren't normalized: divide them by V-1 to get
+// values in [0, 1] for unweighted graph. Zero weight path gives +Inf score.
+//
+// Shortest paths are calculated with Bellman-Ford algorithm, so negative
+// cycles cause panic.
+func HarmonicCentrality(gr DirectedGraphReader, weightFunc ConnectionWeightFunc) map[VertexId]float64 {
+	defer func() {
+		if e:=recover(); e!=nil {
+			panic(erx.NewSequent("Calculate harmonic centrality", e))
+		}
+	}()
+	
+	harmonic := make(map[VertexId]float64)
+	for _, source := range CollectVertexes(gr) {
+		dist, _, ok := BellmanFordSingleSourcePaths(gr, source, weightFunc)
+		if !ok {
+			err := erx.NewError("Negative cycle detected.")
+			err.AddV("source", source)
+			panic(err)
+		}
+		sum := 0.0
+		for node, weight := range dist {
+			if node!=source && weight!=math.MaxFloat64 {
+				sum += 1.0 / weight
+			}
+		}
+		harmonic[source] = sum
+	}
+	return harmonic
+}
+
+// Edge betweenness of unweighted undirected graph with Brandes algorithm.
+//
+// Edge betweenness is a sum over all unordered pairs of nodes of fraction of
+// shortest paths between them, which pass through the edge. It's the value,
+// recalculated on each step of Girvan-Newman community detection. Scores
+// aren't normalized.
+//
+// Edges are unordered, so result is map from tail to head to score, where
+// tail is always not greater than head, just like for undirected connections.
+// Parallel edges share the same entry, and self-loops are never on shortest
+// paths, so they have 0 score.
+func EdgeBetweenness(gr UndirectedGraphEdgesReader) map[VertexId]map[VertexId]float64 {
+	nodes := sortedUndirectedGraphVertexes(gr)
+	neighbours := make(map[VertexId][]VertexId, len(nodes))
+	for _, node := range nodes {
+		neighbours[node] = CollectVertexes(gr.GetNeighbours(node))
+	}
+	betweenness := make(map[VertexId]map[VertexId]float64)
+	for conn := range gr.EdgesIter() {
+		edge := NewUndirectedConnection(conn.Tail, conn.Head).Connection
+		if _, ok := betweenness[edge.Tail]; !ok {
+			betweenness[edge.Tail] = make(map[VertexId]float64)
+		}
+		betweenness[edge.Tail][edge.Head] = 0.0
+	}
+	
+	for _, source := range nodes {
+		// nodes in order of non-decreasing distance from source
+		order := make([]VertexId, 0, len(nodes))
+		pred := make(map[VertexId][]VertexId)
+		sigma := map[VertexId]float64{source:1.0}
+		dist := map[VertexId]int{source:0}
+		queue := []VertexId{source}
+		for len(queue)>0 {
+			curNode := queue[0]
+			queue = queue[1:]
+			order = append(order, curNode)
+			for _, nextNode := range neighbours[curNode] {
+				if _, ok := dist[nextNode]; !ok {
+					dist[nextNode] = dist[curNode] + 1
+					queue = append(queue, nextNode)
+				}
+				if dist[nextNode]==dist[curNode]+1 {
+					sigma[nextNode] += sigma[curNode]
+					pred[nextNode] = append(pred[nextNode], curNode)
+				}
+			}
+		}
+		
+		delta := make(map[VertexId]float64)
+		for i:=len(order)-1; i>0; i-- {
+			node := order[i]
+			for _, prevNode := range pred[node] {
+				share := sigma[prevNode] / sigma[node] * (1.0 + delta[node])
+				edge := NewUndirectedConnection(prevNode, node).Connection
+				// each unordered pair is counted from both ends
+				betweenness[edge.Tail][edge.Head] += share / 2.0
+				delta[prevNode] += share
+			}
+		}
+	}
+	return betweenness
+}
+
+// Single source pass of Brandes algorithm.
+//
+// Returns dependencies of source on all other nodes, which are on shortest
+// paths from source. Accessors map isn't changed.
+func brandesSourceDependencies(accessors map[VertexId][]VertexId, source VertexId, weightFunc ConnectionWeightFunc) map[VertexId]float64 {
+	// nodes in order of non-decreasing distance from source
+	order := make([]VertexId, 0, len(accessors))
+	// predecessors of each node in shortest paths
+	pred := make(map[VertexId][]VertexId)
+	// shortest paths count for each node
+	sigma := make(map[VertexId]float64)
+	sigma[source] = 1.0
+	
+	if weightFunc==nil {
+		dist := make(map[VertexId]int)
+		dist[source] = 0
+		queue := make([]VertexId, 0, 10)
+		queue = append(queue, source)
+		for len(queue)>0 {
+			curNode := queue[0]
+			queue = queue[1:]
+			order = append(order, curNode)
+			for _, nextNode := range accessors[curNode] {
+				if _, ok := dist[nextNode]; !ok {
+					dist[nextNode] = dist[curNode] + 1
+					queue = append(queue, nextNode)
+				}
+				if dist[nextNode]==dist[curNode]+1 {
+					sigma[nextNode] += sigma[curNode]
+					pred[nextNode] = append(pred[nextNode], curNode)
+				}
+			}
+		}
+	} else {
+		dist := make(map[VertexId]float64)
+		dist[source] = 0.0
+		settled := make(map[VertexId]bool)
+		q := NewPriorityQueue(10)
+		q.Push(source, 0.0)
+		for q.Len()>0 {
+			curNode, _ := q.Pop()
+			settled[curNode] = true
+			order = append(order, curNode)
+			for _, nextNode := range accessors[curNode] {
+				if settled[nextNode] {
+					continue
+				}
+				arcWeight := weightFunc(curNode, nextNode)
+				if arcWeight < 0 {
+					err := erx.NewError("Negative weight detected")
+					err.AddV("head", curNode)
+					err.AddV("tail", nextNode)
+					err.AddV("weight", arcWeight)
+					panic(err)
+				}
+				nextWeight := dist[curNode] + arcWeight
+				if knownWeight, ok := dist[nextNode]; !ok || nextWeight<knownWeight {
+					dist[nextNode] = nextWeight
+					sigma[nextNode] = sigma[curNode]
+					pred[nextNode] = []VertexId{curNode}
+					q.Push(nextNode, nextWeight)
+				} else if nextWeight==knownWeight {
+					sigma[nextNode] += sigma[curNode]
+					pred[nextNode] = append(pred[nextNode], curNode)
+				}
+			}
+		}
+	}
+	
+	delta := make(map[VertexId]float64)
+	for i:=len(order)-1; i>0; i-- {
+		node := order[i]
+		for _, prevNode := range pred[node] {
+			delta[prevNode] += sigma[prevNode] / sigma[node] * (1.0 + delta[node])
+		}
+	}
+	delta[source] = 0.0, false
+	return delta
+}
+
+// Local clustering coefficient of undirected graph node.
+//
+// Fraction of pairs of node neighbours, which are connected with each other.
+// Self-loops are ignored. Coefficient of node with less than 2 neighbours is
+// defined as 0.
+func LocalClusteringCoefficient(gr UndirectedGraphEdgesReader, node VertexId) float64 {
+	neighbours := make([]VertexId, 0, 10)
+	for neighbour := range gr.GetNeighbours(node).VertexesIter() {
+		if neighbour!=node {
+			neighbours = append(neighbours, neighbour)
+		}
+	}
+	if len(neighbours)<2 {
+		return 0.0
+	}
+	
+	connectedCnt := 0
+	for i, node1 := range neighbours {
+		for _, node2 := range neighbours[i+1:] {
+			if gr.CheckEdge(node1, node2) {
+				connectedCnt++
+			}
+		}
+	}
+	pairsCnt := len(neighbours) * (len(neighbours) - 1) / 2
+	return float64(connectedCnt) / float64(pairsCnt)
+}
+
+// Global clustering coefficient of undirected graph.
+//
+// Average of local clustering coefficients over nodes with at least 2
+// neighbours. Nodes with less neighbours are excluded both from sum and from
+// nodes count. If there are no such nodes, then coefficient is 0.
+func GlobalClusteringCoefficient(gr UndirectedGraphReader) float64 {
+	sum := 0.0
+	nodesCnt := 0
+	for _, node := range CollectVertexes(gr) {
+		degree := 0
+		for neighbour := range gr.GetNeighbours(node).VertexesIter() {
+			if neighbour!=node {
+				degree++
+			}
+		}
+		if degree>=2 {
+			sum += LocalClusteringCoefficient(gr, node)
+			nodesCnt++
+		}
+	}
+	if nodesCnt==0 {
+		return 0.0
+	}
+	return sum / float64(nodesCnt)
+}
+
+// Degree assortativity coefficient of undirected graph.
+//
+// Pearson correlation of degrees at the ends of edges, where each edge is
+// taken in both directions, so value is in [-1, 1]: positive when nodes tend
+// to connect with nodes of similar degree, and negative otherwise. Degrees
+// are counted with Degree function.
+//
+// Correlation isn't defined for graph without edges and for graph, where all
+// edges connect nodes of equal degrees (e.g. regular graph), and NaN is
+// returned in these cases.
+func DegreeAssortativity(gr UndirectedGraphEdgesReader) float64 {
+	degrees := make(map[VertexId]float64)
+	degree := func(node VertexId) float64 {
+		if d, ok := degrees[node]; ok {
+			return d
+		}
+		d := float64(Degree(gr, node))
+		degrees[node] = d
+		return d
+	}
+	
+	edgesCnt := 0.0
+	// sums of degrees products, of half sums and of half sums of squares
+	productsSum, sum, squaresSum := 0.0, 0.0, 0.0
+	for conn := range gr.EdgesIter() {
+		j, k := degree(conn.Tail), degree(conn.Head)
+		edgesCnt++
+		productsSum += j * k
+		sum += (j + k) / 2.0
+		squaresSum += (j*j + k*k) / 2.0
+	}
+	if edgesCnt==0 {
+		return math.NaN()
+	}
+	mean := sum / edgesCnt
+	variance := squaresSum / edgesCnt - mean * mean
+	if variance==0 {
+		return math.NaN()
+	}
+	return (productsSum / edgesCnt - mean * mean) / variance
+}
+
+// Eccentricities of all graph nodes, calculated with all-pairs shortest paths.
+func eccentricities(gr DirectedGraphReader, weightFunc ConnectionWeightFunc) map[VertexId]float64 {
+	dist := FloydWarshall(gr, weightFunc)
+	if dist==nil {
+		panic(erx.NewError("Negative cycle detected."))
+	}
+	result := make(map[VertexId]float64, len(dist))
+	for node, nodeDist := range dist {
+		result[node] = maxFiniteDistance(nodeDist)
+	}
+	return result
+}
+
+func maxFiniteDistance(dist map[VertexId]float64) float64 {
+	maxDist := 0.0
+	for _, weight := range dist {
+		if weight!=math.MaxFloat64 && weight>maxDist {
+			maxDist = weight
+		}
+	}
+	return maxDist
+}
+
+// Breadth-first search in tree from start node.
+//
+// Returns the farthest node (with smallest id among equally far ones),
+// distances from start and predecessors of nodes in search tree.
+func treeFarthestNode(gr UndirectedGraphEdgesReader, start VertexId) (VertexId, map[VertexId]int, map[VertexId]VertexId) {
+	dist := map[VertexId]int{start:0}
+	prev := make(map[VertexId]VertexId)
+	farthest := start
+	queue := []VertexId{start}
+	for len(queue)>0 {
+		curNode := queue[0]
+		queue = queue[1:]
+		if dist[curNode]>dist[farthest] || (dist[curNode]==dist[farthest] && curNode<farthest) {
+			farthest = curNode
+		}
+		for nextNode := range gr.GetNeighbours(curNode).VertexesIter() {
+			if _, ok := dist[nextNode]; !ok {
+				dist[nextNode] = dist[curNode] + 1
+				prev[nextNode] = curNode
+				queue = append(queue, nextNode)
+			}
+		}
+	}
+	return farthest, dist, prev
+}
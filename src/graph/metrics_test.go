@@ -0,0 +1,327 @@
+package graph
+
+import (
+	"math"
+	"testing"
+	"github.com/orfjackal/gospec/src/gospec"
+	. "github.com/orfjackal/gospec/src/gospec"
+)
+
+func EccentricitySpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	
+	c.Specify("Hop-based eccentricity", func() {
+		c.Expect(Eccentricity(gr, 1, SimpleWeightFunc), Equals, 3.0)
+		c.Expect(Eccentricity(gr, 2, SimpleWeightFunc), Equals, 2.0)
+		c.Expect(Eccentricity(gr, 4, SimpleWeightFunc), Equals, 1.0)
+	})
+	
+	c.Specify("Node without accessors", func() {
+		c.Expect(Eccentricity(gr, 5, SimpleWeightFunc), Equals, 0.0)
+	})
+	
+	c.Specify("Custom weights", func() {
+		weightFunc := func(tail, head VertexId) float64 {
+			if tail==2 && head==4 {
+				return 10.0
+			}
+			return 1.0
+		}
+		c.Expect(Eccentricity(gr, 1, weightFunc), Equals, 4.0)
+	})
+}
+
+func DiameterRadiusSpec(c gospec.Context) {
+	c.Specify("Directed graph", func() {
+		gr := generateDirectedGraph1()
+		c.Expect(Diameter(gr, SimpleWeightFunc), Equals, 3.0)
+		c.Expect(Radius(gr, SimpleWeightFunc), Equals, 0.0)
+	})
+	
+	c.Specify("Directed cycle", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>3>4>1")
+		c.Expect(Diameter(gr, SimpleWeightFunc), Equals, 3.0)
+		c.Expect(Radius(gr, SimpleWeightFunc), Equals, 3.0)
+	})
+	
+	c.Specify("Disconnected graph", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>1")
+		ReadDgraphLine(gr, "3>4>5>3")
+		c.Expect(Diameter(gr, SimpleWeightFunc), Equals, 2.0)
+		c.Expect(Radius(gr, SimpleWeightFunc), Equals, 1.0)
+	})
+	
+	c.Specify("Empty graph", func() {
+		gr := NewDirectedMap()
+		c.Expect(Diameter(gr, SimpleWeightFunc), Equals, 0.0)
+		c.Expect(Radius(gr, SimpleWeightFunc), Equals, 0.0)
+	})
+}
+
+func TreeDiameterSpec(c gospec.Context) {
+	c.Specify("Tree with branches", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-4")
+		ReadUgraphLine(gr, "2-5-6-7")
+		ReadUgraphLine(gr, "3-8")
+		diameter, path := TreeDiameter(gr)
+		c.Expect(diameter, Equals, 5)
+		// 4 and 8 are equally far from 7, smallest id is taken
+		c.Expect(path, ContainsInOrder, Values(VertexId(7), VertexId(6), VertexId(5), VertexId(2), VertexId(3), VertexId(4)))
+	})
+	
+	c.Specify("Single vertex", func() {
+		gr := NewUndirectedMap()
+		gr.AddNode(3)
+		diameter, path := TreeDiameter(gr)
+		c.Expect(diameter, Equals, 0)
+		c.Expect(path, ContainsExactly, Values(VertexId(3)))
+	})
+	
+	c.Specify("Not a tree", func() {
+		defer func() {
+			c.Expect(recover(), Not(IsNil))
+		}()
+		TreeDiameter(CycleGraph(5))
+	})
+}
+
+func PageRankSpec(c gospec.Context) {
+	c.Specify("Ranks sum is 1", func() {
+		gr := generateDirectedGraph1()
+		rank := PageRank(gr, 0.85, 100, 1e-10)
+		c.Expect(len(rank), Equals, 6)
+		sum := 0.0
+		for _, nodeRank := range rank {
+			sum += nodeRank
+		}
+		c.Expect(sum, IsWithin(1e-9), 1.0)
+		c.Expect(rank[5] > rank[1], IsTrue)
+	})
+	
+	c.Specify("Cycle has uniform ranks", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>3>4>1")
+		rank := PageRank(gr, 0.85, 100, 1e-10)
+		for node:=VertexId(1); node<=4; node++ {
+			c.Expect(rank[node], IsWithin(1e-9), 0.25)
+		}
+	})
+	
+	c.Specify("Star with dangling center", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "2>1")
+		ReadDgraphLine(gr, "3>1")
+		ReadDgraphLine(gr, "4>1")
+		rank := PageRank(gr, 0.85, 1000, 1e-12)
+		// a = 0.15/4 + 0.85*b/4, b = 1 - 3a
+		c.Expect(rank[1], IsWithin(1e-6), 0.541984733)
+		c.Expect(rank[2], IsWithin(1e-6), 0.152671756)
+		c.Expect(rank[3], IsWithin(1e-6), 0.152671756)
+	})
+	
+	c.Specify("Early stop", func() {
+		gr := generateDirectedGraph1()
+		rank1 := PageRank(gr, 0.85, 1, 0.0)
+		rank2 := PageRank(gr, 0.85, 1000, 1.0)
+		for node, nodeRank := range rank1 {
+			c.Expect(rank2[node], Equals, nodeRank)
+		}
+	})
+}
+
+func BetweennessCentralitySpec(c gospec.Context) {
+	c.Specify("Chain", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>3>4")
+		centrality := BetweennessCentrality(gr, nil)
+		c.Expect(centrality[1], Equals, 0.0)
+		c.Expect(centrality[2], Equals, 2.0)
+		c.Expect(centrality[3], Equals, 2.0)
+		c.Expect(centrality[4], Equals, 0.0)
+	})
+	
+	c.Specify("Paths are split between equal alternatives", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>4")
+		ReadDgraphLine(gr, "1>3>4")
+		centrality := BetweennessCentrality(gr, nil)
+		c.Expect(centrality[2], Equals, 0.5)
+		c.Expect(centrality[3], Equals, 0.5)
+		c.Expect(centrality[1], Equals, 0.0)
+		
+		weightedCentrality := BetweennessCentrality(gr, SimpleWeightFunc)
+		for node, score := range centrality {
+			c.Expect(weightedCentrality[node], Equals, score)
+		}
+	})
+	
+	c.Specify("Weighted graph", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>4")
+		ReadDgraphLine(gr, "1>3>4")
+		weightFunc := func(tail, head VertexId) float64 {
+			if tail==3 {
+				return 5.0
+			}
+			return 1.0
+		}
+		centrality := BetweennessCentrality(gr, weightFunc)
+		c.Expect(centrality[2], Equals, 1.0)
+		c.Expect(centrality[3], Equals, 0.0)
+	})
+}
+
+func HarmonicCentralitySpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	
+	c.Specify("Hop-based harmonic centrality", func() {
+		harmonic := HarmonicCentrality(gr, SimpleWeightFunc)
+		// 1: 2(1), 6(1), 3(2), 4(2), 5(3)
+		c.Expect(harmonic[1], IsWithin(1e-9), 1.0 + 1.0 + 0.5 + 0.5 + 1.0/3.0)
+		c.Expect(harmonic[4], Equals, 1.0)
+		c.Expect(harmonic[5], Equals, 0.0)
+	})
+	
+	c.Specify("Unreachable component contributes nothing", func() {
+		ReadDgraphLine(gr, "7>8>9")
+		harmonic := HarmonicCentrality(gr, SimpleWeightFunc)
+		c.Expect(harmonic[7], Equals, 1.5)
+		c.Expect(harmonic[8], Equals, 1.0)
+		c.Expect(harmonic[1], IsWithin(1e-9), 1.0 + 1.0 + 0.5 + 0.5 + 1.0/3.0)
+	})
+	
+	c.Specify("Weighted harmonic centrality", func() {
+		weightFunc := func(tail, head VertexId) float64 {
+			return 2.0
+		}
+		harmonic := HarmonicCentrality(gr, weightFunc)
+		c.Expect(harmonic[4], Equals, 0.5)
+	})
+}
+
+func EdgeBetweennessSpec(c gospec.Context) {
+	c.Specify("Bridge of barbell graph", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-1")
+		ReadUgraphLine(gr, "4-5-6-4")
+		ReadUgraphLine(gr, "3-4")
+		betweenness := EdgeBetweenness(gr)
+		// all 3*3 pairs from different triangles use the bridge
+		c.Expect(betweenness[3][4], Equals, 9.0)
+		c.Expect(betweenness[1][2], Equals, 1.0)
+		c.Expect(betweenness[1][3], Equals, 4.0)
+		for tail, heads := range betweenness {
+			for head, score := range heads {
+				c.Expect(tail<=head, IsTrue)
+				c.Expect(score<=betweenness[3][4], IsTrue)
+			}
+		}
+	})
+	
+	c.Specify("Paths are split between equal alternatives", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-4-1")
+		betweenness := EdgeBetweenness(gr)
+		for tail, heads := range betweenness {
+			for head, score := range heads {
+				c.Expect(score, Equals, 2.0)
+				c.Expect(gr.CheckEdge(tail, head), IsTrue)
+			}
+		}
+	})
+}
+
+func ClosenessCentralitySpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	
+	c.Specify("Hop-based closeness", func() {
+		closeness := ClosenessCentrality(gr, SimpleWeightFunc)
+		// 1: 2(1), 6(1), 3(2), 4(2), 5(3)
+		c.Expect(closeness[1], Equals, 5.0/9.0)
+		// 4: 5(1)
+		c.Expect(closeness[4], Equals, 1.0)
+		c.Expect(closeness[5], Equals, 0.0)
+		c.Expect(closeness[6], Equals, 0.0)
+	})
+	
+	c.Specify("Weighted closeness", func() {
+		weightFunc := func(tail, head VertexId) float64 {
+			return 2.0
+		}
+		closeness := ClosenessCentrality(gr, weightFunc)
+		c.Expect(closeness[1], Equals, 5.0/18.0)
+		c.Expect(closeness[4], Equals, 0.5)
+	})
+}
+
+func ClusteringCoefficientSpec(c gospec.Context) {
+	gr := NewUndirectedMap()
+	// triangle 1-2-3 with tail 3-4 and isolated node 5
+	ReadUgraphLine(gr, "1-2-3-1")
+	ReadUgraphLine(gr, "3-4")
+	gr.AddNode(5)
+	
+	c.Specify("Local coefficient", func() {
+		c.Expect(LocalClusteringCoefficient(gr, 1), Equals, 1.0)
+		c.Expect(LocalClusteringCoefficient(gr, 3), Equals, 1.0/3.0)
+		c.Expect(LocalClusteringCoefficient(gr, 4), Equals, 0.0)
+		c.Expect(LocalClusteringCoefficient(gr, 5), Equals, 0.0)
+	})
+	
+	c.Specify("Global coefficient excludes nodes with low degree", func() {
+		c.Expect(GlobalClusteringCoefficient(gr), IsWithin(1e-9), (1.0 + 1.0 + 1.0/3.0) / 3.0)
+	})
+	
+	c.Specify("Graph without triangles", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-4-1")
+		c.Expect(GlobalClusteringCoefficient(gr), Equals, 0.0)
+	})
+}
+
+func DegreeAssortativitySpec(c gospec.Context) {
+	c.Specify("Star is disassortative", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2")
+		ReadUgraphLine(gr, "1-3")
+		ReadUgraphLine(gr, "1-4")
+		c.Expect(DegreeAssortativity(gr), IsWithin(1e-9), -1.0)
+	})
+	
+	c.Specify("Path", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-4")
+		// degrees pairs (1,2), (2,2), (2,1) in both directions
+		c.Expect(DegreeAssortativity(gr), IsWithin(1e-9), -0.5)
+	})
+	
+	c.Specify("Components of different degrees are assortative", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2")
+		ReadUgraphLine(gr, "3-4-5-3")
+		c.Expect(DegreeAssortativity(gr), IsWithin(1e-9), 1.0)
+	})
+	
+	c.Specify("Undefined correlation", func() {
+		c.Expect(math.IsNaN(DegreeAssortativity(NewUndirectedMap())), IsTrue)
+		c.Expect(math.IsNaN(DegreeAssortativity(CycleGraph(5))), IsTrue)
+	})
+}
+
+func TestMetrics(t *testing.T) {
+	r := gospec.NewRunner()
+	r.AddSpec(EccentricitySpec)
+	r.AddSpec(DiameterRadiusSpec)
+	r.AddSpec(TreeDiameterSpec)
+	r.AddSpec(PageRankSpec)
+	r.AddSpec(BetweennessCentralitySpec)
+	r.AddSpec(EdgeBetweennessSpec)
+	r.AddSpec(ClosenessCentralitySpec)
+	r.AddSpec(HarmonicCentralitySpec)
+	r.AddSpec(ClusteringCoefficientSpec)
+	r.AddSpec(DegreeAssortativitySpec)
+	gospec.MainGoTest(r, t)
+}
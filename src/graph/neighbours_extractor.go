@@ -58,6 +58,44 @@ func NewMgraphOutNeighboursExtractor(gr MixedGraphConnectionsReader) OutNeighbou
 	return OutNeighboursExtractor(&mgraphOutNeighboursExtractor{mgraph:gr})
 }
 
+type snapshotOutNeighboursExtractor struct {
+	neighbours map[VertexId][]VertexId
+}
+
+func (e *snapshotOutNeighboursExtractor) GetOutNeighbours(node VertexId) VertexesIterable {
+	neighbours := e.neighbours[node]
+	iterator := func() <-chan VertexId {
+		ch := make(chan VertexId)
+		go func() {
+			for _, nextNode := range neighbours {
+				ch <- nextNode
+			}
+			close(ch)
+		}()
+		return ch
+	}
+	
+	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator})
+}
+
+// Extract neighbours from adjacency snapshot of base extractor.
+//
+// Neighbours of all given vertexes are collected from base extractor once,
+// and after that they are served from memory. Useful for many searches on
+// the same graph. Snapshot doesn't reflect any later changes of underlying
+// graph, and vertexes not from the list haven't any neighbours.
+func NewSnapshotExtractor(base OutNeighboursExtractor, vertexes []VertexId) OutNeighboursExtractor {
+	e := &snapshotOutNeighboursExtractor{
+		neighbours: make(map[VertexId][]VertexId, len(vertexes)),
+	}
+	for _, node := range vertexes {
+		if _, ok := e.neighbours[node]; !ok {
+			e.neighbours[node] = CollectVertexes(base.GetOutNeighbours(node))
+		}
+	}
+	return OutNeighboursExtractor(e)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 
 
@@ -114,3 +152,101 @@ func (e *mgraphInNeighboursExtractor) GetInNeighbours(node VertexId) VertexesIte
 func NewMgraphInNeighboursExtractor(gr MixedGraphConnectionsReader) InNeighboursExtractor {
 	return InNeighboursExtractor(&mgraphInNeighboursExtractor{mgraph:gr})
 }
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Neighbour node together with weight of connection to it.
+type WeightedVertex struct {
+	Node VertexId
+	Weight float64
+}
+
+// Extract all vertexes, which are accessible from given node, with weights of
+// connections to them.
+//
+// Neighbours are returned as slice, so implementations with precomputed
+// weights don't spend anything per connection.
+type WeightedNeighboursExtractor interface {
+	GetOutNeighboursWeighted(node VertexId) []WeightedVertex
+}
+
+type weightFuncNeighboursExtractor struct {
+	base OutNeighboursExtractor
+	weightFunction ConnectionWeightFunc
+}
+
+func (e *weightFuncNeighboursExtractor) GetOutNeighboursWeighted(node VertexId) []WeightedVertex {
+	return e.getOutNeighboursWeightedSkipping(node, nil)
+}
+
+// Weighted neighbours except skipped ones, for which weight function isn't
+// called at all.
+func (e *weightFuncNeighboursExtractor) getOutNeighboursWeightedSkipping(node VertexId, skip map[VertexId]bool) []WeightedVertex {
+	neighbours := make([]WeightedVertex, 0, 10)
+	for nextNode := range e.base.GetOutNeighbours(node).VertexesIter() {
+		if skip[nextNode] {
+			continue
+		}
+		neighbours = append(neighbours, WeightedVertex{Node:nextNode, Weight:e.weightFunction(node, nextNode)})
+	}
+	return neighbours
+}
+
+// Extract weighted neighbours from base extractor, calculating weights with
+// weightFunction.
+func NewWeightedNeighboursExtractor(base OutNeighboursExtractor, weightFunction ConnectionWeightFunc) WeightedNeighboursExtractor {
+	return WeightedNeighboursExtractor(&weightFuncNeighboursExtractor{base:base, weightFunction:weightFunction})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+type maskedOutNeighboursExtractor struct {
+	base OutNeighboursExtractor
+	excludedNodes map[VertexId]bool
+	excludedArcs map[VertexId]map[VertexId]bool
+}
+
+func (e *maskedOutNeighboursExtractor) GetOutNeighbours(node VertexId) VertexesIterable {
+	iterator := func() <-chan VertexId {
+		ch := make(chan VertexId)
+		go func() {
+			if !e.excludedNodes[node] {
+				excludedArcs := e.excludedArcs[node]
+				for nextNode := range e.base.GetOutNeighbours(node).VertexesIter() {
+					if e.excludedNodes[nextNode] || excludedArcs[nextNode] {
+						continue
+					}
+					ch <- nextNode
+				}
+			}
+			close(ch)
+		}()
+		return ch
+	}
+	
+	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator})
+}
+
+// Extract neighbours from base extractor, skipping excluded nodes.
+//
+// Excluded node is never returned as neighbour, and it has no neighbours
+// itself, so any search through such extractor works like excluded nodes
+// are removed from graph. Underlying graph isn't changed.
+//
+// excluded map is used as is, without copying.
+func NewFilteredNeighboursExtractor(base OutNeighboursExtractor, excluded map[VertexId]bool) OutNeighboursExtractor {
+	return OutNeighboursExtractor(newMaskedOutNeighboursExtractor(base, excluded, nil))
+}
+
+// Extract neighbours from base extractor, hiding some nodes and arcs.
+//
+// Excluded node is never returned as neighbour, and it has no neighbours
+// itself. Excluded arc tail->head hides head node from tail node neighbours.
+// Underlying graph isn't changed.
+func newMaskedOutNeighboursExtractor(base OutNeighboursExtractor, excludedNodes map[VertexId]bool, excludedArcs map[VertexId]map[VertexId]bool) *maskedOutNeighboursExtractor {
+	return &maskedOutNeighboursExtractor{
+		base:base,
+		excludedNodes:excludedNodes,
+		excludedArcs:excludedArcs,
+	}
+}
@@ -3,6 +3,7 @@ package graph
 import (
 	"fmt"
 	"io"
+	"os"
 	"strings"
 	"strconv"
 )
@@ -142,3 +143,103 @@ func PlotUgraphToDot(gr UndirectedGraphReader, wr io.Writer, nodeStyleFunc DotNo
 	PlotConnectionsToDot(EdgesToTypedConnIterable(gr), "--", wr, connStyleFunc)
 	wr.Write([]byte("}\n"))
 }
+
+// Write directed graph in graphviz dot format.
+//
+// Graph is written as digraph with given name (name is omitted if empty):
+// node declaration for every vertex, so isolated vertexes are shown too, and
+// one "tail -> head" line per arc. Vertexes are written as their numeric ids,
+// so output can be piped directly to dot utility.
+//
+// Returns first write error, if any.
+func WriteDot(wr io.Writer, gr DirectedGraphReader, name string) os.Error {
+	return writeDot(wr, "digraph", name, "->", gr, gr.ArcsIter(), nil)
+}
+
+// Write directed graph in graphviz dot format with arcs weights.
+//
+// Works like WriteDot, but each arc has label attribute with weight(tail, head)
+// value, formatted with %g verb.
+func WriteDotWeighted(wr io.Writer, gr DirectedGraphReader, weight ConnectionWeightFunc, name string) os.Error {
+	return writeDot(wr, "digraph", name, "->", gr, gr.ArcsIter(), weight)
+}
+
+// Write undirected graph in graphviz dot format.
+//
+// Works like WriteDot, but graph is written as graph with "node1 -- node2"
+// line per edge.
+func WriteDotUndirected(wr io.Writer, gr UndirectedGraphReader, name string) os.Error {
+	return writeDot(wr, "graph", name, "--", gr, gr.EdgesIter(), nil)
+}
+
+// If weight function isn't nil, it's used to label connections.
+func writeDot(wr io.Writer, graphType, name, separator string, nodesIter VertexesIterable, connections <-chan Connection, weight ConnectionWeightFunc) os.Error {
+	var err os.Error
+	write := func(format string, args ...interface{}) {
+		if err==nil {
+			_, err = fmt.Fprintf(wr, format, args...)
+		}
+	}
+	
+	if name=="" {
+		write("%v {\n", graphType)
+	} else {
+		write("%v %v {\n", graphType, strconv.Quote(name))
+	}
+	// reading all vertexes and connections till the end even after error to
+	// stop iterators goroutines
+	for node := range nodesIter.VertexesIter() {
+		write("\t%v;\n", node)
+	}
+	for conn := range connections {
+		if weight==nil {
+			write("\t%v %v %v;\n", conn.Tail, separator, conn.Head)
+		} else {
+			write("\t%v %v %v [label=\"%g\"];\n", conn.Tail, separator, conn.Head, weight(conn.Tail, conn.Head))
+		}
+	}
+	write("}\n")
+	
+	return err
+}
+
+// Write mixed graph in GraphML format.
+//
+// Every vertex is written as <node> element with "n<vertex id>" id. Every
+// connection is written as <edge> element with directed attribute: "true" for
+// arcs and "false" for undirected edges. Vertexes ids are numbers, so no
+// escaping is needed.
+//
+// Returns first write error, if any.
+func WriteGraphML(wr io.Writer, gr MixedGraphReader) os.Error {
+	var err os.Error
+	write := func(format string, args ...interface{}) {
+		if err==nil {
+			_, err = fmt.Fprintf(wr, format, args...)
+		}
+	}
+	
+	write("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	write("<graphml xmlns=\"http://graphml.graphdrawing.org/xmlns\"\n")
+	write("    xmlns:xsi=\"http://www.w3.org/2001/XMLSchema-instance\"\n")
+	write("    xsi:schemaLocation=\"http://graphml.graphdrawing.org/xmlns http://graphml.graphdrawing.org/xmlns/1.0/graphml.xsd\">\n")
+	write("  <graph id=\"G\" edgedefault=\"directed\">\n")
+	// reading all vertexes and connections till the end even after error to
+	// stop iterators goroutines
+	for node := range gr.VertexesIter() {
+		write("    <node id=\"n%v\"/>\n", node)
+	}
+	edgeId := 0
+	for conn := range gr.TypedConnectionsIter() {
+		directed := "true"
+		if conn.Type==CT_UNDIRECTED {
+			directed = "false"
+		}
+		write("    <edge id=\"e%v\" source=\"n%v\" target=\"n%v\" directed=\"%v\"/>\n", edgeId, conn.Tail, conn.Head, directed)
+		edgeId++
+	}
+	write("  </graph>\n")
+	write("</graphml>\n")
+	
+	return err
+}
@@ -30,3 +30,87 @@ func TestOutput(t *testing.T) {
 	gospec.MainGoTest(r, t)
 }
 */
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/orfjackal/gospec/src/gospec"
+	. "github.com/orfjackal/gospec/src/gospec"
+)
+
+func WriteDotSpec(c gospec.Context) {
+	c.Specify("Directed graph with isolated node", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>3")
+		gr.AddNode(4)
+		buf := bytes.NewBufferString("")
+		err := WriteDot(buf, gr, "test")
+		c.Expect(err, IsNil)
+		lines := strings.Split(buf.String(), "\n", -1)
+		c.Expect(lines[0], Equals, "digraph \"test\" {")
+		c.Expect(lines[len(lines)-2], Equals, "}")
+		c.Expect(lines, Contains, "\t4;")
+		c.Expect(lines, Contains, "\t1 -> 2;")
+		c.Expect(lines, Contains, "\t2 -> 3;")
+		c.Expect(len(lines), Equals, 9)
+	})
+	
+	c.Specify("Undirected graph", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "2-1")
+		buf := bytes.NewBufferString("")
+		err := WriteDotUndirected(buf, gr, "")
+		c.Expect(err, IsNil)
+		lines := strings.Split(buf.String(), "\n", -1)
+		c.Expect(lines[0], Equals, "graph {")
+		c.Expect(lines, Contains, "\t1 -- 2;")
+		c.Expect(len(lines), Equals, 6)
+	})
+}
+
+func WriteDotWeightedSpec(c gospec.Context) {
+	gr := NewDirectedMap()
+	ReadDgraphLine(gr, "1>2>3")
+	weightFunc := func(tail, head VertexId) float64 {
+		if tail==1 {
+			return 0.5
+		}
+		return 2.0
+	}
+	buf := bytes.NewBufferString("")
+	err := WriteDotWeighted(buf, gr, weightFunc, "weighted")
+	c.Expect(err, IsNil)
+	lines := strings.Split(buf.String(), "\n", -1)
+	c.Expect(lines[0], Equals, "digraph \"weighted\" {")
+	c.Expect(lines, Contains, "\t1 -> 2 [label=\"0.5\"];")
+	c.Expect(lines, Contains, "\t2 -> 3 [label=\"2\"];")
+	c.Expect(lines, Contains, "\t3;")
+}
+
+func WriteGraphMLSpec(c gospec.Context) {
+	gr := NewMixedMap()
+	gr.AddArc(1, 2)
+	gr.AddEdge(2, 3)
+	gr.AddNode(4)
+	buf := bytes.NewBufferString("")
+	err := WriteGraphML(buf, gr)
+	c.Expect(err, IsNil)
+	out := buf.String()
+	c.Expect(strings.HasPrefix(out, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<graphml "), IsTrue)
+	c.Expect(strings.HasSuffix(out, "  </graph>\n</graphml>\n"), IsTrue)
+	lines := strings.Split(out, "\n", -1)
+	c.Expect(lines, Contains, "    <node id=\"n4\"/>")
+	// edges ids depend on connections order
+	c.Expect(strings.Contains(out, "\" source=\"n1\" target=\"n2\" directed=\"true\"/>\n"), IsTrue)
+	c.Expect(strings.Contains(out, "\" source=\"n2\" target=\"n3\" directed=\"false\"/>\n"), IsTrue)
+}
+
+func TestOutput(t *testing.T) {
+	r := gospec.NewRunner()
+	r.AddSpec(WriteDotSpec)
+	r.AddSpec(WriteDotWeightedSpec)
+	r.AddSpec(WriteGraphMLSpec)
+	gospec.MainGoTest(r, t)
+}
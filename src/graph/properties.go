@@ -0,0 +1,285 @@
+package graph
+
+import (
+	"sort"
+)
+
+// Count of arcs, incoming to node in directed graph.
+//
+// Counted via GetPredecessors, so self-loop counts once (node is its own
+// predecessor).
+func InDegree(gr DirectedGraphArcsReader, node VertexId) int {
+	return countVertexes(gr.GetPredecessors(node))
+}
+
+// Count of arcs, outgoing from node in directed graph.
+//
+// Counted via GetAccessors, so self-loop counts once (node is its own
+// accessor).
+func OutDegree(gr DirectedGraphArcsReader, node VertexId) int {
+	return countVertexes(gr.GetAccessors(node))
+}
+
+// Count of edges, connected to node in undirected graph.
+//
+// Counted via GetNeighbours, so self-loop counts once (node is its own
+// neighbour), not twice as in classic graph theory definition.
+func Degree(gr UndirectedGraphEdgesReader, node VertexId) int {
+	return countVertexes(gr.GetNeighbours(node))
+}
+
+// Count of incoming arcs, outgoing arcs and edges, connected to node in mixed
+// graph.
+//
+// Self-loops are counted once, just like in InDegree, OutDegree and Degree.
+func MixedDegree(gr MixedGraphConnectionsReader, node VertexId) (inDegree, outDegree, degree int) {
+	inDegree = InDegree(gr, node)
+	outDegree = OutDegree(gr, node)
+	degree = Degree(gr, node)
+	return
+}
+
+// Check if undirected graph is bipartite.
+//
+// Each connected component is colored independently with breadth-first
+// search, using colors 0 and 1. If graph is bipartite, then color is returned
+// for every vertex, so that each edge connects vertexes of different colors.
+// Otherwise (odd cycle or self-loop is found) returns false and nil map.
+func IsBipartite(gr UndirectedGraphReader) (bool, map[VertexId]int) {
+	colors := make(map[VertexId]int)
+	for _, startNode := range CollectVertexes(gr) {
+		if _, ok := colors[startNode]; ok {
+			continue
+		}
+		colors[startNode] = 0
+		queue := make([]VertexId, 0, 10)
+		queue = append(queue, startNode)
+		for len(queue)>0 {
+			curNode := queue[0]
+			queue = queue[1:]
+			conflict := false
+			for nextNode := range gr.GetNeighbours(curNode).VertexesIter() {
+				if conflict {
+					// just reading neighbours till the end
+					continue
+				}
+				if color, ok := colors[nextNode]; !ok {
+					colors[nextNode] = 1 - colors[curNode]
+					queue = append(queue, nextNode)
+				} else if color==colors[curNode] {
+					conflict = true
+				}
+			}
+			if conflict {
+				return false, nil
+			}
+		}
+	}
+	return true, colors
+}
+
+func countVertexes(iter VertexesIterable) int {
+	cnt := 0
+	for _ = range iter.VertexesIter() {
+		cnt++
+	}
+	return cnt
+}
+
+// Arcs count in graph
+//
+// If graph maintains arcs count (has ArcsCnt method, like all graphs in this
+// package), it's used. Otherwise all arcs are iterated.
+func ArcsCount(gr ArcsIterable) int {
+	if counter, ok := gr.(interface { ArcsCnt() int }); ok {
+		return counter.ArcsCnt()
+	}
+	cnt := 0
+	for _ = range gr.ArcsIter() {
+		cnt++
+	}
+	return cnt
+}
+
+// Edges count in graph
+//
+// If graph maintains edges count (has EdgesCnt method, like all graphs in
+// this package), it's used. Otherwise all edges are iterated.
+func EdgesCount(gr EdgesIterable) int {
+	if counter, ok := gr.(interface { EdgesCnt() int }); ok {
+		return counter.EdgesCnt()
+	}
+	cnt := 0
+	for _ = range gr.EdgesIter() {
+		cnt++
+	}
+	return cnt
+}
+
+// Vertexes with arc to itself in directed graph.
+//
+// Vertexes are returned in increasing id order, each vertex only once. Use it
+// to validate input before running algorithms: Dijkstra-based searches and
+// MaxFlow ignore self-loops, but Bellman-Ford and Floyd-Warshall algorithms
+// treat self-loop with negative weight as negative cycle.
+func SelfLoops(gr DirectedGraphArcsReader) []VertexId {
+	loops := make([]VertexId, 0, 10)
+	found := make(map[VertexId]bool)
+	for conn := range gr.ArcsIter() {
+		if conn.Tail==conn.Head && !found[conn.Tail] {
+			found[conn.Tail] = true
+			loops = append(loops, conn.Tail)
+		}
+	}
+	sort.Sort(Vertexes(loops))
+	return loops
+}
+
+// Check if directed graph has any arc from vertex to itself.
+func HasSelfLoops(gr DirectedGraphArcsReader) bool {
+	return len(SelfLoops(gr))>0
+}
+
+// Arcs, which appear more than once in directed graph.
+//
+// Result is count of appearances for each such arc: result[tail][head]. Empty
+// map means that graph is simple with respect to arcs. Graphs from this
+// package never contain parallel arcs, so it's useful for other readers,
+// like arcs lists, before running algorithms, which assume simple graph.
+func ParallelArcs(gr ArcsIterable) map[VertexId]map[VertexId]int {
+	counts := make(map[VertexId]map[VertexId]int)
+	for conn := range gr.ArcsIter() {
+		if _, ok := counts[conn.Tail]; !ok {
+			counts[conn.Tail] = make(map[VertexId]int)
+		}
+		counts[conn.Tail][conn.Head]++
+	}
+	return connectionsAppearingTwice(counts)
+}
+
+// Edges, which appear more than once in undirected graph.
+//
+// Same as ParallelArcs, but edges a-b and b-a are the same, and in result tail
+// is always not greater than head.
+func ParallelEdges(gr EdgesIterable) map[VertexId]map[VertexId]int {
+	counts := make(map[VertexId]map[VertexId]int)
+	for conn := range gr.EdgesIter() {
+		tail, head := conn.Tail, conn.Head
+		if tail>head {
+			tail, head = head, tail
+		}
+		if _, ok := counts[tail]; !ok {
+			counts[tail] = make(map[VertexId]int)
+		}
+		counts[tail][head]++
+	}
+	return connectionsAppearingTwice(counts)
+}
+
+// Leave only connections with count greater than one.
+func connectionsAppearingTwice(counts map[VertexId]map[VertexId]int) map[VertexId]map[VertexId]int {
+	res := make(map[VertexId]map[VertexId]int)
+	for tail, heads := range counts {
+		for head, cnt := range heads {
+			if cnt<2 {
+				continue
+			}
+			if _, ok := res[tail]; !ok {
+				res[tail] = make(map[VertexId]int)
+			}
+			res[tail][head] = cnt
+		}
+	}
+	return res
+}
+
+// All vertexes of undirected graph, sorted by id.
+//
+// Vertexes are taken from graph, when it's VertexesIterable, and from edges
+// otherwise, so isolated vertexes are lost in the latter case.
+func sortedUndirectedGraphVertexes(gr UndirectedGraphEdgesReader) []VertexId {
+	if nodesIter, ok := gr.(VertexesIterable); ok {
+		return SortedVertexes(nodesIter)
+	}
+	nodesSet := make(map[VertexId]bool)
+	for conn := range gr.EdgesIter() {
+		nodesSet[conn.Tail] = true
+		nodesSet[conn.Head] = true
+	}
+	nodes := make([]VertexId, 0, len(nodesSet))
+	for node := range nodesSet {
+		nodes = append(nodes, node)
+	}
+	sort.Sort(Vertexes(nodes))
+	return nodes
+}
+
+// Check if undirected graph is a tree
+//
+// Tree is connected graph with exactly V-1 edges (so it's acyclic). Graph
+// with single vertex and without edges is a tree, and graph without vertexes
+// isn't. Self-loop and parallel edges are cycles, so they are never in tree.
+//
+// Vertexes are taken from graph, when it's VertexesIterable, and from edges
+// otherwise, so for edges-only reader isolated vertex can't be found.
+func IsTree(gr UndirectedGraphEdgesReader) bool {
+	nodes := sortedUndirectedGraphVertexes(gr)
+	if len(nodes)==0 || gr.EdgesCnt()!=len(nodes)-1 {
+		return false
+	}
+	
+	visited := map[VertexId]bool{nodes[0]:true}
+	queue := []VertexId{nodes[0]}
+	for len(queue)>0 {
+		curNode := queue[0]
+		queue = queue[1:]
+		for nextNode := range gr.GetNeighbours(curNode).VertexesIter() {
+			if !visited[nextNode] {
+				visited[nextNode] = true
+				queue = append(queue, nextNode)
+			}
+		}
+	}
+	return len(visited)==len(nodes)
+}
+
+// Check if directed graph is an arborescence (directed tree) with given root
+//
+// Root must exist in graph and mustn't have incoming arcs, every other vertex
+// must have exactly one incoming arc, and all vertexes must be reachable from
+// root. Self-loop is an incoming arc, so vertex with self-loop is never in
+// arborescence. Single vertex graph is an arborescence with this vertex as
+// root.
+func IsArborescence(gr DirectedGraphReader, root VertexId) bool {
+	if !gr.CheckNode(root) || gr.ArcsCnt()!=gr.Order()-1 {
+		return false
+	}
+	// vertexes channel is read to the end even if wrong degree is found
+	degreesOk := true
+	for node := range gr.VertexesIter() {
+		expectedInDegree := 1
+		if node==root {
+			expectedInDegree = 0
+		}
+		if degreesOk && InDegree(gr, node)!=expectedInDegree {
+			degreesOk = false
+		}
+	}
+	if !degreesOk {
+		return false
+	}
+	
+	visited := map[VertexId]bool{root:true}
+	queue := []VertexId{root}
+	for len(queue)>0 {
+		curNode := queue[0]
+		queue = queue[1:]
+		for nextNode := range gr.GetAccessors(curNode).VertexesIter() {
+			if !visited[nextNode] {
+				visited[nextNode] = true
+				queue = append(queue, nextNode)
+			}
+		}
+	}
+	return len(visited)==gr.Order()
+}
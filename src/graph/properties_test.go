@@ -0,0 +1,254 @@
+package graph
+
+import (
+	"testing"
+	"github.com/orfjackal/gospec/src/gospec"
+	. "github.com/orfjackal/gospec/src/gospec"
+)
+
+func DegreeSpec(c gospec.Context) {
+	c.Specify("Directed graph", func() {
+		gr := generateDirectedGraph1()
+		c.Expect(InDegree(gr, 4), Equals, 2)
+		c.Expect(OutDegree(gr, 4), Equals, 1)
+		c.Expect(InDegree(gr, 1), Equals, 0)
+		c.Expect(OutDegree(gr, 2), Equals, 3)
+	})
+	
+	c.Specify("Directed self-loop counts once", func() {
+		gr := NewDirectedMap()
+		gr.AddArc(1, 1)
+		gr.AddArc(1, 2)
+		c.Expect(InDegree(gr, 1), Equals, 1)
+		c.Expect(OutDegree(gr, 1), Equals, 2)
+	})
+	
+	c.Specify("Undirected graph", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-1")
+		gr.AddEdge(3, 4)
+		c.Expect(Degree(gr, 3), Equals, 3)
+		c.Expect(Degree(gr, 4), Equals, 1)
+	})
+	
+	c.Specify("Undirected self-loop counts once", func() {
+		gr := NewUndirectedMap()
+		gr.AddEdge(1, 1)
+		gr.AddEdge(1, 2)
+		c.Expect(Degree(gr, 1), Equals, 2)
+	})
+	
+	c.Specify("Mixed graph", func() {
+		gr := generateMixedGraph1()
+		inDegree, outDegree, degree := MixedDegree(gr, 4)
+		c.Expect(inDegree, Equals, 2)
+		c.Expect(outDegree, Equals, 1)
+		c.Expect(degree, Equals, 1)
+	})
+}
+
+func IsBipartiteSpec(c gospec.Context) {
+	c.Specify("Even cycle", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-4-1")
+		isBipartite, colors := IsBipartite(gr)
+		c.Expect(isBipartite, IsTrue)
+		c.Expect(len(colors), Equals, 4)
+		for conn := range gr.EdgesIter() {
+			c.Expect(colors[conn.Tail], Not(Equals), colors[conn.Head])
+		}
+	})
+	
+	c.Specify("Odd cycle", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-4-5-1")
+		isBipartite, colors := IsBipartite(gr)
+		c.Expect(isBipartite, IsFalse)
+		c.Expect(colors, IsNil)
+	})
+	
+	c.Specify("Disconnected graph", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3")
+		ReadUgraphLine(gr, "4-5")
+		gr.AddNode(6)
+		isBipartite, colors := IsBipartite(gr)
+		c.Expect(isBipartite, IsTrue)
+		c.Expect(len(colors), Equals, 6)
+		c.Expect(colors[1], Equals, colors[3])
+		c.Expect(colors[4], Not(Equals), colors[5])
+		
+		ReadUgraphLine(gr, "6-7-8-6")
+		isBipartite, _ = IsBipartite(gr)
+		c.Expect(isBipartite, IsFalse)
+	})
+}
+
+// Connections list without counters, so they have to be iterated
+type connectionsList []Connection
+
+func (l connectionsList) ArcsIter() <-chan Connection {
+	ch := make(chan Connection)
+	go func() {
+		for _, conn := range l {
+			ch <- conn
+		}
+		close(ch)
+	}()
+	return ch
+}
+
+func (l connectionsList) EdgesIter() <-chan Connection {
+	return l.ArcsIter()
+}
+
+func ConnectionsCountSpec(c gospec.Context) {
+	c.Specify("Graph counters are used", func() {
+		gr := generateDirectedGraph1()
+		c.Expect(ArcsCount(gr), Equals, 7)
+		ugr := NewUndirectedMap()
+		ReadUgraphLine(ugr, "1-2-3-1")
+		c.Expect(EdgesCount(ugr), Equals, 3)
+	})
+	
+	c.Specify("Connections are iterated without counters", func() {
+		l := connectionsList{Connection{1, 2}, Connection{2, 3}, Connection{3, 1}}
+		c.Expect(ArcsCount(l), Equals, 3)
+		c.Expect(EdgesCount(l), Equals, 3)
+		c.Expect(ArcsCount(connectionsList{}), Equals, 0)
+	})
+}
+
+func SelfLoopsSpec(c gospec.Context) {
+	c.Specify("Graph without self-loops", func() {
+		gr := generateDirectedGraph1()
+		c.Expect(len(SelfLoops(gr)), Equals, 0)
+		c.Expect(HasSelfLoops(gr), IsFalse)
+	})
+	
+	c.Specify("Self-loops are found", func() {
+		gr := generateDirectedGraph1()
+		gr.AddArc(4, 4)
+		gr.AddArc(1, 1)
+		c.Expect(SelfLoops(gr), ContainsInOrder, Values(VertexId(1), VertexId(4)))
+		c.Expect(HasSelfLoops(gr), IsTrue)
+	})
+	
+	c.Specify("Self-loop with negative weight", func() {
+		gr := generateDirectedGraph1()
+		gr.AddArc(2, 2)
+		weightFunc := func(tail, head VertexId) float64 {
+			if tail==head {
+				return -1.0
+			}
+			return 1.0
+		}
+		weight, ok := CheckPathDijkstra(NewDgraphOutNeighboursExtractor(gr), 1, 5, nil, weightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 3.0)
+		c.Expect(FloydWarshall(gr, weightFunc), IsNil)
+	})
+}
+
+func ParallelConnectionsSpec(c gospec.Context) {
+	c.Specify("Simple graph", func() {
+		c.Expect(len(ParallelArcs(generateDirectedGraph1())), Equals, 0)
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-1")
+		c.Expect(len(ParallelEdges(gr)), Equals, 0)
+	})
+	
+	c.Specify("Parallel arcs are counted", func() {
+		l := connectionsList{Connection{1, 2}, Connection{2, 1}, Connection{1, 2}, Connection{2, 3}, Connection{1, 2}, Connection{3, 3}, Connection{3, 3}}
+		arcs := ParallelArcs(l)
+		c.Expect(len(arcs), Equals, 2)
+		c.Expect(len(arcs[1]), Equals, 1)
+		c.Expect(arcs[1][2], Equals, 3)
+		c.Expect(arcs[3][3], Equals, 2)
+	})
+	
+	c.Specify("Parallel edges are counted in both directions", func() {
+		l := connectionsList{Connection{1, 2}, Connection{2, 1}, Connection{2, 3}, Connection{3, 2}, Connection{3, 2}, Connection{3, 4}}
+		edges := ParallelEdges(l)
+		c.Expect(len(edges), Equals, 2)
+		c.Expect(edges[1][2], Equals, 2)
+		c.Expect(edges[2][3], Equals, 3)
+		_, ok := edges[3]
+		c.Expect(ok, IsFalse)
+	})
+}
+
+func IsTreeSpec(c gospec.Context) {
+	c.Specify("Trees", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-4")
+		ReadUgraphLine(gr, "2-5")
+		c.Expect(IsTree(gr), IsTrue)
+		single := NewUndirectedMap()
+		single.AddNode(1)
+		c.Expect(IsTree(single), IsTrue)
+	})
+	
+	c.Specify("Not trees", func() {
+		c.Expect(IsTree(NewUndirectedMap()), IsFalse)
+		c.Expect(IsTree(CycleGraph(4)), IsFalse)
+		// forest with V-2 edges
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3")
+		gr.AddNode(4)
+		c.Expect(IsTree(gr), IsFalse)
+		// V-1 edges, but with cycle and isolated vertex
+		gr.AddEdge(1, 3)
+		c.Expect(IsTree(gr), IsFalse)
+		loop := NewUndirectedMap()
+		ReadUgraphLine(loop, "1-2")
+		loop.AddEdge(2, 2)
+		c.Expect(IsTree(loop), IsFalse)
+	})
+}
+
+func IsArborescenceSpec(c gospec.Context) {
+	gr := NewDirectedMap()
+	ReadDgraphLine(gr, "1>2>3")
+	ReadDgraphLine(gr, "1>4>5")
+	ReadDgraphLine(gr, "4>6")
+	
+	c.Specify("Arborescence from root only", func() {
+		c.Expect(IsArborescence(gr, 1), IsTrue)
+		c.Expect(IsArborescence(gr, 4), IsFalse)
+		c.Expect(IsArborescence(gr, 7), IsFalse)
+	})
+	
+	c.Specify("Vertex with two incoming arcs", func() {
+		gr.RemoveArc(4, 6)
+		gr.AddArc(3, 5)
+		c.Expect(IsArborescence(gr, 1), IsFalse)
+	})
+	
+	c.Specify("Unreachable cycle", func() {
+		// in-degrees are right, but 5 and 6 are in cycle
+		gr.RemoveArc(4, 5)
+		gr.AddArc(6, 5)
+		gr.RemoveArc(4, 6)
+		gr.AddArc(5, 6)
+		c.Expect(IsArborescence(gr, 1), IsFalse)
+	})
+	
+	c.Specify("Single vertex", func() {
+		single := NewDirectedMap()
+		single.AddNode(1)
+		c.Expect(IsArborescence(single, 1), IsTrue)
+	})
+}
+
+func TestProperties(t *testing.T) {
+	r := gospec.NewRunner()
+	r.AddSpec(DegreeSpec)
+	r.AddSpec(IsBipartiteSpec)
+	r.AddSpec(ConnectionsCountSpec)
+	r.AddSpec(SelfLoopsSpec)
+	r.AddSpec(ParallelConnectionsSpec)
+	r.AddSpec(IsTreeSpec)
+	r.AddSpec(IsArborescenceSpec)
+	gospec.MainGoTest(r, t)
+}
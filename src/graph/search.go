@@ -1,7 +1,9 @@
 package graph
 
 import (
+	"container/heap"
 	"math"
+	"sort"
 
 	"github.com/StepLg/go-erx/src/erx"
 )
@@ -22,42 +24,1197 @@ type ConnectionWeightFunc func(head, tail VertexId) float64
 
 type StopFunc func(node VertexId, sumWeight float64) bool
 
+// Path-aware stop function: gets tentative path from source node to the
+// checked node (both included) and it's total weight.
+type StopFuncPath func(path []VertexId, sumWeight float64) bool
+
+// Estimated path weight from node to destination node, used by A* search.
+type HeuristicFunc func(node VertexId) float64
+
 func SimpleWeightFunc(head, tail VertexId) float64 {
 	return float64(1.0)
 }
 
-// Generic check path algorithm for all graph types
-// 
-// Checking path between from and to nodes, using getNeighbours function
-// to figure out connected nodes on each step of algorithm.
-// 
-// stopFunc is used to cut bad paths using user-defined criteria
-// 
-// weightFunction calculates total path weight
-// 
-// As a result CheckPathDijkstra returns total weight of path, if it exists.
-func CheckPathDijkstra(neighboursExtractor OutNeighboursExtractor, from, to VertexId, stopFunc StopFunc, weightFunction ConnectionWeightFunc) (float64, bool) {
+// Weight function, which returns the same weight for all connections.
+//
+// SimpleWeightFunc is the same as ConstantWeight(1.0).
+func ConstantWeight(weight float64) ConnectionWeightFunc {
+	return func(tail, head VertexId) float64 {
+		return weight
+	}
+}
+
+// Weight function, which calculates weight of each connection only once.
+//
+// Results of weightFunc are cached in map by tail and head, so it's useful for
+// expensive weight functions, which are called for the same connections many
+// times. Cache is never cleared and grows with count of distinct connections.
+// Result isn't safe for concurrent use.
+func MemoizeWeight(weightFunc ConnectionWeightFunc) ConnectionWeightFunc {
+	cache := make(map[VertexId]map[VertexId]float64)
+	return func(tail, head VertexId) float64 {
+		tailCache, ok := cache[tail]
+		if !ok {
+			tailCache = make(map[VertexId]float64)
+			cache[tail] = tailCache
+		}
+		if weight, ok := tailCache[head]; ok {
+			return weight
+		}
+		weight := weightFunc(tail, head)
+		tailCache[head] = weight
+		return weight
+	}
+}
+
+// Weight function, which multiplies weights of weightFunc by factor.
+//
+// Dijkstra-based searches panic on negative weights, so negative factor with
+// positive weights makes them panic.
+func ScaleWeight(weightFunc ConnectionWeightFunc, factor float64) ConnectionWeightFunc {
+	return func(tail, head VertexId) float64 {
+		return factor * weightFunc(tail, head)
+	}
+}
+
+// Weight function, which is sum of two weight functions, e.g. distance and
+// toll.
+//
+// Sum is negative if any of weights is negative enough, and then
+// Dijkstra-based searches panic.
+func AddWeights(weightFunc1, weightFunc2 ConnectionWeightFunc) ConnectionWeightFunc {
+	return func(tail, head VertexId) float64 {
+		return weightFunc1(tail, head) + weightFunc2(tail, head)
+	}
+}
+
+// Generic check path algorithm for all graph types
+// 
+// Checking path between from and to nodes, using getNeighbours function
+// to figure out connected nodes on each step of algorithm.
+// 
+// stopFunc is used to cut bad paths using user-defined criteria. It isn't
+// called for to node, so reaching it is never cut.
+// 
+// weightFunction calculates total path weight
+// 
+// As a result CheckPathDijkstra returns total weight of path, if it exists.
+// Weight of to node is final only when it's extracted from queue, so returned
+// weight is the minimal one, even if to node was reached by heavier path first.
+//
+// Self-loops are ignored: node is settled before it's neighbours are checked,
+// so arc to itself is skipped without even calculating it's weight. Weight
+// function isn't called for arcs to other settled nodes too.
+func CheckPathDijkstra(neighboursExtractor OutNeighboursExtractor, from, to VertexId, stopFunc StopFunc, weightFunction ConnectionWeightFunc) (float64, bool) {
+	return CheckPathDijkstraWeighted(NewWeightedNeighboursExtractor(neighboursExtractor, weightFunction), from, to, stopFunc)
+}
+
+// Check path with Dijkstra algorithm, using precomputed connections weights
+//
+// Works exactly like CheckPathDijkstra, but takes connections weights from
+// neighbours extractor instead of calling weight function for each of them.
+func CheckPathDijkstraWeighted(neighboursExtractor WeightedNeighboursExtractor, from, to VertexId, stopFunc StopFunc) (float64, bool) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Check path graph with Dijkstra algorithm", e)
+			err.AddV("from", from)
+			err.AddV("to", to)
+			panic(err)
+		}
+	}()
+	
+	if from==to {
+		return 0.0, true
+	}
+	
+	q := NewPriorityQueue(10)
+	q.Push(from, 0.0)
+	// best known path weight to each reached node
+	dist := make(map[VertexId]float64)
+	dist[from] = 0.0
+	// nodes, which were already extracted from queue
+	settled := make(map[VertexId]bool)
+	getNeighbours := func(node VertexId) []WeightedVertex {
+		return neighboursExtractor.GetOutNeighboursWeighted(node)
+	}
+	if e, ok := neighboursExtractor.(*weightFuncNeighboursExtractor); ok {
+		// weights of arcs to settled nodes aren't needed, so they aren't calculated
+		getNeighbours = func(node VertexId) []WeightedVertex {
+			return e.getOutNeighboursWeightedSkipping(node, settled)
+		}
+	}
+	
+	for q.Len()>0 {
+		curNode, curWeight := q.Pop()
+		if _, ok := settled[curNode]; ok {
+			continue
+		}
+		// node is extracted with minimal weight, so it's distance is final
+		if curNode==to {
+			return curWeight, true
+		}
+		settled[curNode] = true
+	
+		for _, next := range getNeighbours(curNode) {
+			nextNode := next.Node
+			if _, ok := settled[nextNode]; ok {
+				continue
+			}
+			arcWeight := next.Weight
+			if arcWeight < 0 {
+				err := erx.NewError("Negative weight detected")
+				err.AddV("head", curNode)
+				err.AddV("tail", nextNode)
+				err.AddV("weight", arcWeight)
+				panic(err)
+			}
+			nextWeight := curWeight + arcWeight
+			if knownWeight, ok := dist[nextNode]; ok && knownWeight<=nextWeight {
+				continue
+			}
+			// to node isn't checked by stopFunc, as it's the end of path
+			if nextNode==to || stopFunc==nil || !stopFunc(nextNode, nextWeight) {
+				dist[nextNode] = nextWeight
+				q.Push(nextNode, nextWeight)
+			}
+		}
+	}
+	
+	return -1.0, false
+}
+
+// Shortest path between two nodes with Dijkstra algorithm
+//
+// Works exactly like CheckPathDijkstra, but also keeps previous node for each
+// reached node and restores the shortest path itself. Path contains both from
+// and to nodes, so if from==to, then path consists of single node.
+//
+// If there is no path between nodes, then nil path and false are returned.
+func ShortestPathDijkstra(neighboursExtractor OutNeighboursExtractor, from, to VertexId, stopFunc StopFunc, weightFunction ConnectionWeightFunc) ([]VertexId, float64, bool) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Search shortest path with Dijkstra algorithm", e)
+			err.AddV("from", from)
+			err.AddV("to", to)
+			panic(err)
+		}
+	}()
+	
+	if from==to {
+		return []VertexId{from}, 0.0, true
+	}
+	
+	prev, _, weight, ok := shortestPathDijkstra(neighboursExtractor, from, to, stopFunc, nil, weightFunction)
+	if !ok {
+		return nil, -1.0, false
+	}
+	return pathFromPredecessors(prev, from, to), weight, true
+}
+
+// Shortest path between two nodes with Dijkstra algorithm and weights of it's arcs
+//
+// Works exactly like ShortestPathDijkstra, but also returns weight of each
+// arc in path: edgeWeights[i] is the weight of arc from path[i] to path[i+1],
+// so len(edgeWeights)==len(path)-1. Weights are saved during search, so weight
+// function isn't called again for the path arcs.
+//
+// If there is no path between nodes, then nil path, nil weights and false are
+// returned.
+func ShortestPathDetailed(neighboursExtractor OutNeighboursExtractor, from, to VertexId, weightFunction ConnectionWeightFunc) (path []VertexId, edgeWeights []float64, total float64, ok bool) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Search detailed shortest path with Dijkstra algorithm", e)
+			err.AddV("from", from)
+			err.AddV("to", to)
+			panic(err)
+		}
+	}()
+	
+	if from==to {
+		return []VertexId{from}, []float64{}, 0.0, true
+	}
+	
+	prev, prevWeight, total, ok := shortestPathDijkstra(neighboursExtractor, from, to, nil, nil, weightFunction)
+	if !ok {
+		return nil, nil, -1.0, false
+	}
+	path = pathFromPredecessors(prev, from, to)
+	edgeWeights = make([]float64, len(path)-1)
+	for i:=1; i<len(path); i++ {
+		edgeWeights[i-1] = prevWeight[path[i]]
+	}
+	return path, edgeWeights, total, true
+}
+
+// Shortest path between two nodes with Dijkstra algorithm and path-aware stop function
+//
+// Works exactly like ShortestPathDijkstra, but stopFunc gets the whole
+// tentative path to the checked node, so it can cut paths using their
+// history, e.g. reject node which already appears in path. Only the best
+// known path is kept for each node, so if stopFunc rejects it, then the node
+// isn't reachable through the same previous nodes with heavier paths.
+func ShortestPathDijkstraPathStop(neighboursExtractor OutNeighboursExtractor, from, to VertexId, stopFunc StopFuncPath, weightFunction ConnectionWeightFunc) ([]VertexId, float64, bool) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Search shortest path with Dijkstra algorithm and path stop function", e)
+			err.AddV("from", from)
+			err.AddV("to", to)
+			panic(err)
+		}
+	}()
+	
+	if from==to {
+		return []VertexId{from}, 0.0, true
+	}
+	
+	prev, _, weight, ok := shortestPathDijkstra(neighboursExtractor, from, to, nil, stopFunc, weightFunction)
+	if !ok {
+		return nil, -1.0, false
+	}
+	return pathFromPredecessors(prev, from, to), weight, true
+}
+
+// Shortest path between two nodes, which avoids given vertexes
+//
+// Works exactly like ShortestPathDijkstra, but vertexes from avoid set are
+// treated as nonexistent, so it's a shortcut for one-off queries instead of
+// composing filtered neighbours extractor. Avoid set is never changed, nil
+// set avoids nothing. If from or to node is avoided, then there is no path,
+// even if from==to.
+func ShortestPathAvoiding(neighboursExtractor OutNeighboursExtractor, from, to VertexId, avoid map[VertexId]bool, weightFunction ConnectionWeightFunc) ([]VertexId, float64, bool) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Search shortest path avoiding vertexes", e)
+			err.AddV("from", from)
+			err.AddV("to", to)
+			err.AddV("avoid", avoid)
+			panic(err)
+		}
+	}()
+	
+	if avoid[from] || avoid[to] {
+		return nil, -1.0, false
+	}
+	if from==to {
+		return []VertexId{from}, 0.0, true
+	}
+	
+	stopFunc := func(node VertexId, sumWeight float64) bool {
+		return avoid[node]
+	}
+	prev, _, weight, ok := shortestPathDijkstra(neighboursExtractor, from, to, stopFunc, nil, weightFunction)
+	if !ok {
+		return nil, -1.0, false
+	}
+	return pathFromPredecessors(prev, from, to), weight, true
+}
+
+// Widest path (path with maximal bottleneck capacity) between two nodes
+//
+// Path bottleneck is the minimal capacity of it's arcs. Search is the same as
+// in Dijkstra algorithm, but node key is the best known bottleneck of path to
+// it, and node with maximal key is extracted first from NewMaxPriorityQueue.
+// Capacities may be any numbers, including negative ones.
+//
+// Path contains both from and to nodes. Bottleneck of single node path
+// (from==to) is +Inf. If there is no path between nodes, then nil path and
+// false are returned.
+func WidestPath(neighboursExtractor OutNeighboursExtractor, from, to VertexId, capacity ConnectionWeightFunc) ([]VertexId, float64, bool) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Search widest path", e)
+			err.AddV("from", from)
+			err.AddV("to", to)
+			panic(err)
+		}
+	}()
+	
+	if from==to {
+		return []VertexId{from}, math.Inf(1), true
+	}
+	
+	q := NewMaxPriorityQueue(10)
+	q.Push(from, math.Inf(1))
+	// best known bottleneck of path to each reached node
+	width := map[VertexId]float64{from:math.Inf(1)}
+	prev := make(map[VertexId]VertexId)
+	settled := make(map[VertexId]bool)
+	for q.Len()>0 {
+		curNode, curWidth := q.Pop()
+		if curNode==to {
+			return pathFromPredecessors(prev, from, to), curWidth, true
+		}
+		settled[curNode] = true
+		
+		for nextNode := range neighboursExtractor.GetOutNeighbours(curNode).VertexesIter() {
+			if settled[nextNode] {
+				continue
+			}
+			nextWidth := capacity(curNode, nextNode)
+			if curWidth<nextWidth {
+				nextWidth = curWidth
+			}
+			if knownWidth, ok := width[nextNode]; ok && knownWidth>=nextWidth {
+				continue
+			}
+			width[nextNode] = nextWidth
+			prev[nextNode] = curNode
+			q.Push(nextNode, nextWidth)
+		}
+	}
+	
+	return nil, -1.0, false
+}
+
+// Dijkstra search with predecessors tracking
+//
+// Returns previous node and weight of arc from it for each node in shortest
+// path tree, which was built before to node was extracted from queue.
+//
+// Any of stopFunc and stopPathFunc can be nil. Tentative path is restored only
+// if stopPathFunc is set.
+func shortestPathDijkstra(neighboursExtractor OutNeighboursExtractor, from, to VertexId, stopFunc StopFunc, stopPathFunc StopFuncPath, weightFunction ConnectionWeightFunc) (map[VertexId]VertexId, map[VertexId]float64, float64, bool) {
+	q := NewPriorityQueue(10)
+	q.Push(from, 0.0)
+	// best known path weight to each reached node
+	dist := make(map[VertexId]float64)
+	dist[from] = 0.0
+	// previous node in best known path to each reached node
+	prev := make(map[VertexId]VertexId)
+	// weight of arc from previous node in best known path
+	prevWeight := make(map[VertexId]float64)
+	// nodes, which were already extracted from queue
+	settled := make(map[VertexId]bool)
+	
+	for q.Len()>0 {
+		curNode, curWeight := q.Pop()
+		if curNode==to {
+			return prev, prevWeight, curWeight, true
+		}
+		settled[curNode] = true
+	
+		for nextNode := range neighboursExtractor.GetOutNeighbours(curNode).VertexesIter() {
+			if _, ok := settled[nextNode]; ok {
+				continue
+			}
+			arcWeight := weightFunction(curNode, nextNode)
+			if arcWeight < 0 {
+				err := erx.NewError("Negative weight detected")
+				err.AddV("head", curNode)
+				err.AddV("tail", nextNode)
+				err.AddV("weight", arcWeight)
+				panic(err)
+			}
+			nextWeight := curWeight + arcWeight
+			if knownWeight, ok := dist[nextNode]; ok && knownWeight<=nextWeight {
+				continue
+			}
+			if stopFunc!=nil && stopFunc(nextNode, nextWeight) {
+				continue
+			}
+			if stopPathFunc!=nil {
+				path := append(pathFromPredecessors(prev, from, curNode), nextNode)
+				if stopPathFunc(path, nextWeight) {
+					continue
+				}
+			}
+			dist[nextNode] = nextWeight
+			prev[nextNode] = curNode
+			prevWeight[nextNode] = arcWeight
+			q.Push(nextNode, nextWeight)
+		}
+	}
+	
+	return nil, nil, -1.0, false
+}
+
+// Node state in DijkstraSearcher. Mark is valid only if it's query number is
+// the current one, so marks needn't be cleared between queries.
+type dijkstraSearcherMark struct {
+	query int
+	dist float64
+	prev VertexId
+	settled bool
+}
+
+// Reusable Dijkstra search on the same neighbours extractor
+//
+// Priority queue and nodes marks are kept between queries, so repeated
+// searches don't allocate them again. Searcher isn't safe for concurrent use.
+type DijkstraSearcher struct {
+	neighboursExtractor OutNeighboursExtractor
+	weightFunction ConnectionWeightFunc
+	q *PriorityQueue
+	marks map[VertexId]dijkstraSearcherMark
+	query int
+}
+
+// Create reusable Dijkstra searcher.
+func NewDijkstraSearcher(neighboursExtractor OutNeighboursExtractor, weightFunction ConnectionWeightFunc) *DijkstraSearcher {
+	return &DijkstraSearcher{
+		neighboursExtractor: neighboursExtractor,
+		weightFunction: weightFunction,
+		q: NewPriorityQueue(10),
+		marks: make(map[VertexId]dijkstraSearcherMark),
+	}
+}
+
+// Shortest path between two nodes
+//
+// Works exactly like ShortestPathDijkstra without stop function: path
+// contains both from and to nodes, and if there is no path between nodes,
+// then nil path and false are returned.
+func (s *DijkstraSearcher) FindPath(from, to VertexId) ([]VertexId, float64, bool) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Search shortest path with reusable Dijkstra searcher", e)
+			err.AddV("from", from)
+			err.AddV("to", to)
+			panic(err)
+		}
+	}()
+	
+	if from==to {
+		return []VertexId{from}, 0.0, true
+	}
+	
+	s.query++
+	s.q.Clear()
+	s.q.Push(from, 0.0)
+	s.marks[from] = dijkstraSearcherMark{query:s.query, dist:0.0}
+	
+	for s.q.Len()>0 {
+		curNode, curWeight := s.q.Pop()
+		if curNode==to {
+			return s.restorePath(from, to), curWeight, true
+		}
+		curMark := s.marks[curNode]
+		curMark.settled = true
+		s.marks[curNode] = curMark
+	
+		for nextNode := range s.neighboursExtractor.GetOutNeighbours(curNode).VertexesIter() {
+			nextMark, ok := s.marks[nextNode]
+			ok = ok && nextMark.query==s.query
+			if ok && nextMark.settled {
+				continue
+			}
+			arcWeight := s.weightFunction(curNode, nextNode)
+			if arcWeight < 0 {
+				err := erx.NewError("Negative weight detected")
+				err.AddV("head", curNode)
+				err.AddV("tail", nextNode)
+				err.AddV("weight", arcWeight)
+				panic(err)
+			}
+			nextWeight := curWeight + arcWeight
+			if ok && nextMark.dist<=nextWeight {
+				continue
+			}
+			s.marks[nextNode] = dijkstraSearcherMark{query:s.query, dist:nextWeight, prev:curNode}
+			s.q.Push(nextNode, nextWeight)
+		}
+	}
+	
+	return nil, -1.0, false
+}
+
+func (s *DijkstraSearcher) restorePath(from, to VertexId) []VertexId {
+	path := make([]VertexId, 0, 10)
+	for node:=to; node!=from; node=s.marks[node].prev {
+		path = append(path, node)
+	}
+	path = append(path, from)
+	for i:=0; i<len(path)/2; i++ {
+		path[i], path[len(path)-i-1] = path[len(path)-i-1], path[i]
+	}
+	return path
+}
+
+// All shortest paths between two nodes with Dijkstra algorithm
+//
+// Works like AllShortestPathsLimit without limit on paths count.
+func AllShortestPaths(neighboursExtractor OutNeighboursExtractor, from, to VertexId, weightFunction ConnectionWeightFunc) ([][]VertexId, float64, bool) {
+	return AllShortestPathsLimit(neighboursExtractor, from, to, weightFunction, 0)
+}
+
+// All shortest paths between two nodes with Dijkstra algorithm, but not more
+// than maxPaths of them
+//
+// Dijkstra search keeps all previous nodes with optimal path weight for each
+// reached node, and then all paths are restored from these predecessors.
+// Number of shortest paths may grow exponentially (e.g. in grid graphs), so
+// use positive maxPaths to limit it. Non-positive maxPaths means no limit.
+// Each path contains both from and to nodes. Previous nodes are processed in
+// increasing id order, so result is the same for equal graphs.
+//
+// Returns paths and their weight, or nil paths and false if there is no path
+// between nodes.
+func AllShortestPathsLimit(neighboursExtractor OutNeighboursExtractor, from, to VertexId, weightFunction ConnectionWeightFunc, maxPaths int) ([][]VertexId, float64, bool) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Search all shortest paths with Dijkstra algorithm", e)
+			err.AddV("from", from)
+			err.AddV("to", to)
+			panic(err)
+		}
+	}()
+	
+	if from==to {
+		return [][]VertexId{[]VertexId{from}}, 0.0, true
+	}
+	
+	q := NewPriorityQueue(10)
+	q.Push(from, 0.0)
+	// best known path weight to each reached node
+	dist := make(map[VertexId]float64)
+	dist[from] = 0.0
+	// all previous nodes in best known paths to each reached node
+	prev := make(map[VertexId][]VertexId)
+	
+	for q.Len()>0 {
+		curNode, curWeight := q.Pop()
+		if toWeight, ok := dist[to]; ok && curWeight>toWeight {
+			// all nodes of shortest paths to to node are already processed
+			break
+		}
+		
+		for nextNode := range neighboursExtractor.GetOutNeighbours(curNode).VertexesIter() {
+			arcWeight := weightFunction(curNode, nextNode)
+			if arcWeight < 0 {
+				err := erx.NewError("Negative weight detected")
+				err.AddV("head", curNode)
+				err.AddV("tail", nextNode)
+				err.AddV("weight", arcWeight)
+				panic(err)
+			}
+			nextWeight := curWeight + arcWeight
+			knownWeight, ok := dist[nextNode]
+			switch {
+				case !ok || nextWeight<knownWeight:
+					dist[nextNode] = nextWeight
+					prev[nextNode] = []VertexId{curNode}
+					q.Push(nextNode, nextWeight)
+				case nextWeight==knownWeight && nextNode!=from:
+					prev[nextNode] = append(prev[nextNode], curNode)
+			}
+		}
+	}
+	
+	weight, ok := dist[to]
+	if !ok {
+		return nil, -1.0, false
+	}
+	for _, prevNodes := range prev {
+		sort.Sort(Vertexes(prevNodes))
+	}
+	
+	paths := make([][]VertexId, 0, 10)
+	reversedPath := []VertexId{to}
+	onPath := map[VertexId]bool{to:true}
+	var restore func(node VertexId) bool
+	// returns true, if paths limit is reached
+	restore = func(node VertexId) bool {
+		if node==from {
+			path := make([]VertexId, len(reversedPath))
+			for i, pathNode := range reversedPath {
+				path[len(path)-1-i] = pathNode
+			}
+			paths = append(paths, path)
+			return maxPaths>0 && len(paths)>=maxPaths
+		}
+		for _, prevNode := range prev[node] {
+			if onPath[prevNode] {
+				// loop through zero weight arcs
+				continue
+			}
+			onPath[prevNode] = true
+			reversedPath = append(reversedPath, prevNode)
+			stopped := restore(prevNode)
+			reversedPath = reversedPath[0:len(reversedPath)-1]
+			onPath[prevNode] = false, false
+			if stopped {
+				return true
+			}
+		}
+		return false
+	}
+	restore(to)
+	
+	return paths, weight, true
+}
+
+// Shortest path between two nodes with A* algorithm
+//
+// heuristic estimates path weight from given node to the to node. It must be
+// admissible (never overestimate real path weight) and non-negative, otherwise
+// returned path may be not the shortest one. Negative heuristic value causes
+// panic. If heuristic always returns 0, then algorithm is exactly Dijkstra
+// search.
+//
+// Returns path, which contains both from and to nodes, and it's total weight.
+// If there is no path between nodes, then nil path and false are returned.
+// Self-loop never makes path shorter, so it's ignored, but it still causes
+// panic if it's weight is negative.
+func AStarPath(neighboursExtractor OutNeighboursExtractor, from, to VertexId, heuristic HeuristicFunc, weightFunction ConnectionWeightFunc) ([]VertexId, float64, bool) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Search shortest path with A* algorithm", e)
+			err.AddV("from", from)
+			err.AddV("to", to)
+			panic(err)
+		}
+	}()
+	
+	if from==to {
+		return []VertexId{from}, 0.0, true
+	}
+	
+	estimate := func(node VertexId) float64 {
+		h := heuristic(node)
+		if h < 0 {
+			err := erx.NewError("Negative heuristic value detected")
+			err.AddV("node", node)
+			err.AddV("heuristic", h)
+			panic(err)
+		}
+		return h
+	}
+	
+	// queue is ordered by sum of known path weight and heuristic estimate
+	q := NewPriorityQueue(10)
+	q.Push(from, estimate(from))
+	// best known path weight to each reached node
+	dist := make(map[VertexId]float64)
+	dist[from] = 0.0
+	// previous node in best known path to each reached node
+	prev := make(map[VertexId]VertexId)
+	
+	for q.Len()>0 {
+		curNode, _ := q.Pop()
+		curWeight := dist[curNode]
+		if curNode==to {
+			return pathFromPredecessors(prev, from, to), curWeight, true
+		}
+	
+		for nextNode := range neighboursExtractor.GetOutNeighbours(curNode).VertexesIter() {
+			arcWeight := weightFunction(curNode, nextNode)
+			if arcWeight < 0 {
+				err := erx.NewError("Negative weight detected")
+				err.AddV("head", curNode)
+				err.AddV("tail", nextNode)
+				err.AddV("weight", arcWeight)
+				panic(err)
+			}
+			nextWeight := curWeight + arcWeight
+			if knownWeight, ok := dist[nextNode]; ok && knownWeight<=nextWeight {
+				continue
+			}
+			dist[nextNode] = nextWeight
+			prev[nextNode] = curNode
+			q.Push(nextNode, nextWeight + estimate(nextNode))
+		}
+	}
+	
+	return nil, -1.0, false
+}
+
+// Path weight between two nodes with bidirectional Dijkstra algorithm
+//
+// Search grows simultaneously from from node using forward extractor and from
+// to node using backward extractor (for directed graph it's a predecessors
+// extractor, see NewDgraphInNeighboursExtractor). Arc weight in backward
+// search is calculated in original direction: weightFunction(prev, cur).
+//
+// The node where two frontiers meet isn't necessarily on the shortest path,
+// so the best path weight through any touched node is tracked, and search
+// stops only when sum of both frontiers minimal weights isn't less than it.
+//
+// Returns total weight of shortest path, if it exists.
+func BidirectionalPathDijkstra(forward OutNeighboursExtractor, backward InNeighboursExtractor, from, to VertexId, weightFunction ConnectionWeightFunc) (float64, bool) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Check path with bidirectional Dijkstra algorithm", e)
+			err.AddV("from", from)
+			err.AddV("to", to)
+			panic(err)
+		}
+	}()
+	
+	if from==to {
+		return 0.0, true
+	}
+	
+	checkWeight := func(tail, head VertexId) float64 {
+		arcWeight := weightFunction(tail, head)
+		if arcWeight < 0 {
+			err := erx.NewError("Negative weight detected")
+			err.AddV("head", tail)
+			err.AddV("tail", head)
+			err.AddV("weight", arcWeight)
+			panic(err)
+		}
+		return arcWeight
+	}
+	
+	qForward := NewPriorityQueue(10)
+	qForward.Push(from, 0.0)
+	distForward := make(map[VertexId]float64)
+	distForward[from] = 0.0
+	settledForward := make(map[VertexId]bool)
+	
+	qBackward := NewPriorityQueue(10)
+	qBackward.Push(to, 0.0)
+	distBackward := make(map[VertexId]float64)
+	distBackward[to] = 0.0
+	settledBackward := make(map[VertexId]bool)
+	
+	// best path weight through already touched nodes
+	bestWeight := math.MaxFloat64
+	pathExists := false
+	
+	for qForward.Len()>0 && qBackward.Len()>0 {
+		_, forwardMin := qForward.Peek()
+		_, backwardMin := qBackward.Peek()
+		if pathExists && forwardMin + backwardMin >= bestWeight {
+			break
+		}
+		
+		if forwardMin <= backwardMin {
+			curNode, _ := qForward.Pop()
+			settledForward[curNode] = true
+			curWeight := distForward[curNode]
+			for nextNode := range forward.GetOutNeighbours(curNode).VertexesIter() {
+				if _, ok := settledForward[nextNode]; ok {
+					continue
+				}
+				nextWeight := curWeight + checkWeight(curNode, nextNode)
+				if knownWeight, ok := distForward[nextNode]; !ok || nextWeight<knownWeight {
+					distForward[nextNode] = nextWeight
+					qForward.Push(nextNode, nextWeight)
+				}
+				if backWeight, ok := distBackward[nextNode]; ok && nextWeight + backWeight < bestWeight {
+					bestWeight = nextWeight + backWeight
+					pathExists = true
+				}
+			}
+		} else {
+			curNode, _ := qBackward.Pop()
+			settledBackward[curNode] = true
+			curWeight := distBackward[curNode]
+			for prevNode := range backward.GetInNeighbours(curNode).VertexesIter() {
+				if _, ok := settledBackward[prevNode]; ok {
+					continue
+				}
+				prevWeight := curWeight + checkWeight(prevNode, curNode)
+				if knownWeight, ok := distBackward[prevNode]; !ok || prevWeight<knownWeight {
+					distBackward[prevNode] = prevWeight
+					qBackward.Push(prevNode, prevWeight)
+				}
+				if forwardWeight, ok := distForward[prevNode]; ok && prevWeight + forwardWeight < bestWeight {
+					bestWeight = prevWeight + forwardWeight
+					pathExists = true
+				}
+			}
+		}
+	}
+	
+	if !pathExists {
+		return -1.0, false
+	}
+	return bestWeight, true
+}
+
+// Shortest path between two nodes in unweighted graph
+//
+// Breadth-first search, which finds path with minimal number of connections.
+// Path contains both from and to nodes. If there is no path between nodes,
+// then nil path and false are returned.
+func UnweightedShortestPath(neighboursExtractor OutNeighboursExtractor, from, to VertexId) ([]VertexId, bool) {
+	if from==to {
+		return []VertexId{from}, true
+	}
+	
+	// previous node in path for each reached node
+	prev := make(map[VertexId]VertexId)
+	queue := make([]VertexId, 0, 10)
+	queue = append(queue, from)
+	for len(queue)>0 {
+		curNode := queue[0]
+		queue = queue[1:]
+		for nextNode := range neighboursExtractor.GetOutNeighbours(curNode).VertexesIter() {
+			if _, ok := prev[nextNode]; ok || nextNode==from {
+				continue
+			}
+			prev[nextNode] = curNode
+			if nextNode==to {
+				return pathFromPredecessors(prev, from, to), true
+			}
+			queue = append(queue, nextNode)
+		}
+	}
+	
+	return nil, false
+}
+
+// Nearest source for each reachable node with multi-source breadth-first search
+//
+// Search starts from all sources simultaneously and goes level by level, so
+// each node is reached once, by the minimal number of connections from any of
+// sources. If several sources are equally near, then source with smallest id
+// is chosen.
+//
+// Returns nearest source and connections count to it for each node, which is
+// reachable from any of sources. Sources are nearest to themselves with 0
+// distance.
+func NearestSource(neighboursExtractor OutNeighboursExtractor, sources []VertexId) (map[VertexId]VertexId, map[VertexId]int) {
+	nearest := make(map[VertexId]VertexId)
+	dist := make(map[VertexId]int)
+	front := make([]VertexId, 0, len(sources))
+	for _, source := range sources {
+		if _, ok := nearest[source]; ok {
+			continue
+		}
+		nearest[source] = source
+		dist[source] = 0
+		front = append(front, source)
+	}
+	
+	for level:=1; len(front)>0; level++ {
+		nextFront := make([]VertexId, 0, 10)
+		for _, curNode := range front {
+			curSource := nearest[curNode]
+			for nextNode := range neighboursExtractor.GetOutNeighbours(curNode).VertexesIter() {
+				nextDist, ok := dist[nextNode]
+				if !ok {
+					dist[nextNode] = level
+					nearest[nextNode] = curSource
+					nextFront = append(nextFront, nextNode)
+				} else if nextDist==level && curSource<nearest[nextNode] {
+					nearest[nextNode] = curSource
+				}
+			}
+		}
+		front = nextFront
+	}
+	
+	return nearest, dist
+}
+
+// Path weight between two nodes in graph with 0 and 1 connections weights
+//
+// 0-1 breadth-first search: node, reached by connection with weight 0, is
+// added to the front of deque, and reached by connection with weight 1 - to
+// the back, so nodes are extracted in increasing distance order, like in
+// Dijkstra search, but without priority queue. Any other weight causes panic.
+//
+// Returns total weight of shortest path, if it exists.
+func ZeroOneBFS(neighboursExtractor OutNeighboursExtractor, from, to VertexId, weightFunction ConnectionWeightFunc) (float64, bool) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Check path with 0-1 breadth-first search", e)
+			err.AddV("from", from)
+			err.AddV("to", to)
+			panic(err)
+		}
+	}()
+	
+	dist := map[VertexId]float64{from:0.0}
+	settled := make(map[VertexId]bool)
+	d := newVertexesDeque(10)
+	d.PushBack(from)
+	for d.Size()>0 {
+		curNode := d.PopFront()
+		if settled[curNode] {
+			continue
+		}
+		if curNode==to {
+			return dist[curNode], true
+		}
+		settled[curNode] = true
+		
+		for nextNode := range neighboursExtractor.GetOutNeighbours(curNode).VertexesIter() {
+			arcWeight := weightFunction(curNode, nextNode)
+			if arcWeight!=0.0 && arcWeight!=1.0 {
+				err := erx.NewError("Weight must be 0 or 1")
+				err.AddV("head", curNode)
+				err.AddV("tail", nextNode)
+				err.AddV("weight", arcWeight)
+				panic(err)
+			}
+			nextWeight := dist[curNode] + arcWeight
+			if knownWeight, ok := dist[nextNode]; ok && knownWeight<=nextWeight {
+				continue
+			}
+			dist[nextNode] = nextWeight
+			if arcWeight==0.0 {
+				d.PushFront(nextNode)
+			} else {
+				d.PushBack(nextNode)
+			}
+		}
+	}
+	
+	return -1.0, false
+}
+
+// Path between two nodes with iterative deepening depth-first search
+//
+// Runs depth-limited DFS with limits 0, 1, ... maxDepth connections, so the
+// first found path has minimal number of connections, like in
+// UnweightedShortestPath. Only current path is kept in memory, which trades
+// search time for memory on large graphs. Path contains both from and to nodes.
+//
+// If there is no path with at most maxDepth connections, then nil path and
+// false are returned.
+func IterativeDeepeningPath(neighboursExtractor OutNeighboursExtractor, from, to VertexId, maxDepth int) ([]VertexId, bool) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Search path with iterative deepening", e)
+			err.AddV("from", from)
+			err.AddV("to", to)
+			err.AddV("max depth", maxDepth)
+			panic(err)
+		}
+	}()
+	
+	if maxDepth<0 {
+		err := erx.NewError("Negative max depth.")
+		panic(err)
+	}
+	
+	for limit:=0; limit<=maxDepth; limit++ {
+		path := make([]VertexId, 1, limit+1)
+		path[0] = from
+		onPath := map[VertexId]bool{from:true}
+		if path, found := depthLimitedPath(neighboursExtractor, to, path, onPath, limit); found {
+			return path, true
+		}
+	}
+	return nil, false
+}
+
+// Depth-limited DFS from the last node of path.
+//
+// Returns path extended to the to node, if it's reachable with at most limit
+// connections without visiting nodes from path again.
+func depthLimitedPath(neighboursExtractor OutNeighboursExtractor, to VertexId, path []VertexId, onPath map[VertexId]bool, limit int) ([]VertexId, bool) {
+	curNode := path[len(path)-1]
+	if curNode==to {
+		return path, true
+	}
+	if limit==0 {
+		return nil, false
+	}
+	
+	for _, nextNode := range CollectVertexes(neighboursExtractor.GetOutNeighbours(curNode)) {
+		if onPath[nextNode] {
+			continue
+		}
+		onPath[nextNode] = true
+		if res, found := depthLimitedPath(neighboursExtractor, to, append(path, nextNode), onPath, limit-1); found {
+			return res, true
+		}
+		onPath[nextNode] = false, false
+	}
+	return nil, false
+}
+
+// K shortest loopless paths between two nodes with Yen algorithm
+//
+// Returns up to k paths sorted by increasing total weight. Each path contains
+// both from and to nodes. If graph doesn't contain k different loopless paths,
+// then all of them are returned.
+//
+// Each next path is searched with ShortestPathDijkstra from every node of
+// previous path (spur node), while nodes of path till spur node and arcs from
+// spur node, used by already found paths with the same beginning, are
+// temporarily hidden.
+func KShortestPaths(neighboursExtractor OutNeighboursExtractor, from, to VertexId, k int, weightFunction ConnectionWeightFunc) [][]VertexId {
 	defer func() {
 		if e:=recover(); e!=nil {
-			err := erx.NewSequent("Check path graph with Dijkstra algorithm", e)
+			err := erx.NewSequent("Search k shortest paths with Yen algorithm", e)
 			err.AddV("from", from)
 			err.AddV("to", to)
+			err.AddV("k", k)
 			panic(err)
 		}
 	}()
 	
-	if from==to {
-		return 0.0, true
+	paths := make([][]VertexId, 0, k)
+	if k<=0 {
+		return paths
+	}
+	
+	firstPath, _, ok := ShortestPathDijkstra(neighboursExtractor, from, to, nil, weightFunction)
+	if !ok {
+		return paths
+	}
+	paths = append(paths, firstPath)
+	
+	// candidates for next shortest path
+	candidates := make([][]VertexId, 0, 10)
+	candidatesWeights := make([]float64, 0, 10)
+	
+	for len(paths)<k {
+		prevPath := paths[len(paths)-1]
+		for i:=0; i<len(prevPath)-1; i++ {
+			spurNode := prevPath[i]
+			rootPath := prevPath[0:i+1]
+			
+			excludedArcs := make(map[VertexId]map[VertexId]bool)
+			for _, path := range paths {
+				if len(path)>i+1 && pathsEqual(path[0:i+1], rootPath) {
+					if _, ok := excludedArcs[path[i]]; !ok {
+						excludedArcs[path[i]] = make(map[VertexId]bool)
+					}
+					excludedArcs[path[i]][path[i+1]] = true
+				}
+			}
+			excludedNodes := make(map[VertexId]bool)
+			for _, node := range rootPath[0:i] {
+				excludedNodes[node] = true
+			}
+			
+			spurExtractor := newMaskedOutNeighboursExtractor(neighboursExtractor, excludedNodes, excludedArcs)
+			spurPath, _, ok := ShortestPathDijkstra(spurExtractor, spurNode, to, nil, weightFunction)
+			if !ok {
+				continue
+			}
+			
+			candidate := make([]VertexId, i, i+len(spurPath))
+			copy(candidate, rootPath[0:i])
+			candidate = append(candidate, spurPath...)
+			
+			known := false
+			for _, path := range candidates {
+				if pathsEqual(path, candidate) {
+					known = true
+					break
+				}
+			}
+			if !known {
+				candidates = append(candidates, candidate)
+				candidatesWeights = append(candidatesWeights, pathWeight(candidate, weightFunction))
+			}
+		}
+		
+		if len(candidates)==0 {
+			break
+		}
+		
+		bestPos := 0
+		for pos, weight := range candidatesWeights {
+			if weight<candidatesWeights[bestPos] {
+				bestPos = pos
+			}
+		}
+		paths = append(paths, candidates[bestPos])
+		candidates = append(candidates[0:bestPos], candidates[bestPos+1:]...)
+		candidatesWeights = append(candidatesWeights[0:bestPos], candidatesWeights[bestPos+1:]...)
 	}
 	
-	q := newPriorityQueueSimple(10)
-	q.Add(from, 0.0)
+	return paths
+}
+
+// Walk state index with walk weight in SecondShortestPath queue.
+type walkStatesHeapItem struct {
+	state int
+	weight float64
+}
+
+// Min-first container/heap of walk states. States with equal weights are
+// extracted in order of their indexes, i.e. in order of addition.
+type walkStatesHeap []walkStatesHeapItem
+
+func (h walkStatesHeap) Len() int {
+	return len(h)
+}
+
+func (h walkStatesHeap) Less(i, j int) bool {
+	if h[i].weight!=h[j].weight {
+		return h[i].weight<h[j].weight
+	}
+	return h[i].state<h[j].state
+}
+
+func (h walkStatesHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *walkStatesHeap) Push(x interface{}) {
+	*h = append(*h, x.(walkStatesHeapItem))
+}
+
+func (h *walkStatesHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[0:len(old)-1]
+	return item
+}
+
+// Second shortest path between two nodes
+//
+// If simple is true, then path is loopless: it's the second path of
+// KShortestPaths with k=2. Otherwise path is a walk, which may visit the same
+// vertexes (even from and to nodes) several times. It's searched with Dijkstra
+// algorithm, where each node is extracted from queue at most twice, and the
+// second extraction of to node gives the second shortest walk.
+//
+// Second path differs from the shortest one, but may have the same weight. If
+// there is at most one path between nodes, then nil path and false are
+// returned. For from==to the shortest path is the single node, so the second
+// one is a cycle through from node (and there is no simple second path).
+func SecondShortestPath(neighboursExtractor OutNeighboursExtractor, from, to VertexId, simple bool, weightFunction ConnectionWeightFunc) ([]VertexId, float64, bool) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Search second shortest path", e)
+			err.AddV("from", from)
+			err.AddV("to", to)
+			err.AddV("simple", simple)
+			panic(err)
+		}
+	}()
 	
-	for !q.Empty() {
-		curNode, curWeight := q.Next()
-		curWeight = -curWeight // because we inverse weight in priority queue
+	if simple {
+		paths := KShortestPaths(neighboursExtractor, from, to, 2, weightFunction)
+		if len(paths)<2 {
+			return nil, -1.0, false
+		}
+		return paths[1], pathWeight(paths[1], weightFunction), true
+	}
 	
+	// walks are kept as tree of states, each state is the last node of walk
+	// and the index of walk state without this node
+	type walkState struct {
+		node VertexId
+		prev int
+	}
+	states := []walkState{walkState{from, -1}}
+	q := &walkStatesHeap{walkStatesHeapItem{state:0, weight:0.0}}
+	extracted := make(map[VertexId]int)
+	for q.Len()>0 {
+		item := heap.Pop(q).(walkStatesHeapItem)
+		stateId, curWeight := item.state, item.weight
+		curNode := states[stateId].node
+		if extracted[curNode]>=2 {
+			continue
+		}
+		extracted[curNode]++
+		if curNode==to && extracted[curNode]==2 {
+			path := make([]VertexId, 0, 10)
+			for id:=stateId; id!=-1; id=states[id].prev {
+				path = append(path, states[id].node)
+			}
+			for i:=0; i<len(path)/2; i++ {
+				path[i], path[len(path)-i-1] = path[len(path)-i-1], path[i]
+			}
+			return path, curWeight, true
+		}
+		
 		for nextNode := range neighboursExtractor.GetOutNeighbours(curNode).VertexesIter() {
+			if extracted[nextNode]>=2 {
+				continue
+			}
 			arcWeight := weightFunction(curNode, nextNode)
 			if arcWeight < 0 {
 				err := erx.NewError("Negative weight detected")
@@ -66,17 +1223,62 @@ func CheckPathDijkstra(neighboursExtractor OutNeighboursExtractor, from, to Vert
 				err.AddV("weight", arcWeight)
 				panic(err)
 			}
-			nextWeight := curWeight + arcWeight
-			if nextNode==to {
-				return nextWeight, true
-			}
-			if stopFunc==nil || !stopFunc(nextNode, nextWeight) {
-				q.Add(nextNode, -nextWeight)
-			}
+			states = append(states, walkState{nextNode, stateId})
+			heap.Push(q, walkStatesHeapItem{state:len(states)-1, weight:curWeight + arcWeight})
 		}
 	}
 	
-	return -1.0, false
+	return nil, -1.0, false
+}
+
+// Total weight of all connections in path.
+func pathWeight(path []VertexId, weightFunction ConnectionWeightFunc) float64 {
+	weight := 0.0
+	for i:=1; i<len(path); i++ {
+		weight += weightFunction(path[i-1], path[i])
+	}
+	return weight
+}
+
+func pathsEqual(path1, path2 []VertexId) bool {
+	if len(path1)!=len(path2) {
+		return false
+	}
+	for i, node := range path1 {
+		if node!=path2[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Restore path from predecessors map.
+//
+// prev must contain previous node for every node in path except from node.
+// Result path starts with from node and ends with to node.
+func pathFromPredecessors(prev map[VertexId]VertexId, from, to VertexId) []VertexId {
+	path := make([]VertexId, 0, 10)
+	curNode := to
+	for curNode!=from {
+		path = append(path, curNode)
+		prevNode, ok := prev[curNode]
+		if !ok {
+			err := erx.NewError("Can't find previous vertex for vertex in path.")
+			err.AddV("vertex", curNode)
+			err.AddV("cur path", path)
+			panic(err)
+		}
+		curNode = prevNode
+	}
+	path = append(path, from)
+	
+	// reversing path
+	pathLen := len(path)
+	for i:=0; i<pathLen/2; i++ {
+		path[i], path[pathLen-i-1] = path[pathLen-i-1], path[i]
+	}
+	
+	return path
 }
 
 type CheckDirectedPath func(gr DirectedGraphArcsReader, from, to VertexId, stopFunc StopFunc, weightFunction ConnectionWeightFunc) bool
@@ -104,16 +1306,51 @@ func CheckMixedPathDijkstra(gr MixedGraphConnectionsReader, from, to VertexId, s
 //
 // This algorithms doesn't take any loops into paths.
 func GetAllPaths(neighboursExtractor OutNeighboursExtractor, from, to VertexId) <-chan []VertexId {
+	return GetAllPathsCancelable(neighboursExtractor, from, to, nil)
+}
+
+// Get all paths from one node to another with ability to stop search
+//
+// Works like GetAllPaths, but search stops and channel is closed as soon as
+// anything is received from done channel (or it's closed). This allows to stop
+// reading paths before the end without blocking search goroutine forever.
+// nil done channel means that search is never stopped.
+//
+// There is no context package in this Go release, so cancellation is signalled
+// with plain done channel instead of context with GetAllPathsContext name.
+func GetAllPathsCancelable(neighboursExtractor OutNeighboursExtractor, from, to VertexId, done <-chan bool) <-chan []VertexId {
+	return getAllPaths(neighboursExtractor, from, to, 0, done)
+}
+
+// Get all paths from one node to another with no more than maxLen arcs
+//
+// Recursion is pruned as soon as path reaches maxLen arcs, so this is usable
+// on large graphs where full enumeration is intractable. maxLen<=0 means
+// unlimited path length, just like in GetAllPaths.
+func GetAllPathsMaxLen(neighboursExtractor OutNeighboursExtractor, from, to VertexId, maxLen int) <-chan []VertexId {
+	return getAllPaths(neighboursExtractor, from, to, maxLen, nil)
+}
+
+func getAllPaths(neighboursExtractor OutNeighboursExtractor, from, to VertexId, maxLen int, done <-chan bool) <-chan []VertexId {
 	curPath := make([]VertexId, 10)
 	nodesStatus := make(map[VertexId]bool)
 	ch := make(chan []VertexId)
-	go getAllPaths_helper(neighboursExtractor, from, to, curPath, 0, nodesStatus, ch, true)
+	go func() {
+		getAllPaths_helper(neighboursExtractor, from, to, curPath, 0, maxLen, nodesStatus, ch, done)
+		close(ch)
+	}()
 	return ch
 }
 
-func getAllPaths_helper(neighboursExtractor OutNeighboursExtractor, from, to VertexId, curPath []VertexId, pathPos int, nodesStatus map[VertexId]bool, ch chan []VertexId, closeChannel bool) {
+// Returns true if search was stopped with done channel.
+func getAllPaths_helper(neighboursExtractor OutNeighboursExtractor, from, to VertexId, curPath []VertexId, pathPos, maxLen int, nodesStatus map[VertexId]bool, ch chan []VertexId, done <-chan bool) bool {
+	select {
+		case <-done:
+			return true
+		default:
+	}
 	if _, ok := nodesStatus[from]; ok {
-		return
+		return false
 	}
 	if pathPos==len(curPath) {
 		// reallocate curPath slice to add new elements
@@ -128,22 +1365,80 @@ func getAllPaths_helper(neighboursExtractor OutNeighboursExtractor, from, to Ver
 		if pathPos>0 {
 			pathCopy := make([]VertexId, pathPos+1)
 			copy(pathCopy, curPath[0:pathPos+1])
-			ch <- pathCopy
+			select {
+				case ch <- pathCopy:
+				case <-done:
+					return true
+			}
 		}
-		return
+		return false
+	}
+	if maxLen>0 && pathPos>=maxLen {
+		// no more arcs allowed in this path
+		return false
 	}
 	nodesStatus[from] = true
 	
+	stopped := false
 	for nextNode := range neighboursExtractor.GetOutNeighbours(from).VertexesIter() {
-		getAllPaths_helper(neighboursExtractor, nextNode, to, curPath, pathPos+1, nodesStatus, ch, false)
+		if stopped {
+			// just reading neighbours till the end
+			continue
+		}
+		stopped = getAllPaths_helper(neighboursExtractor, nextNode, to, curPath, pathPos+1, maxLen, nodesStatus, ch, done)
 	}
 	
 	nodesStatus[from] = false, false
 	
-	if closeChannel {
-		close(ch)
+	return stopped
+}
+
+// Count all paths from one node to another
+//
+// Does the same search as GetAllPaths, but only counts paths instead of
+// copying them. If there are more than math.MaxUint64 paths, then count stops
+// at math.MaxUint64.
+func CountSimplePaths(neighboursExtractor OutNeighboursExtractor, from, to VertexId) uint64 {
+	return CountSimplePathsMaxLen(neighboursExtractor, from, to, 0)
+}
+
+// Count paths from one node to another with at most maxLen connections
+//
+// Non-positive maxLen means no limit, just like in GetAllPathsMaxLen.
+func CountSimplePathsMaxLen(neighboursExtractor OutNeighboursExtractor, from, to VertexId, maxLen int) uint64 {
+	nodesStatus := make(map[VertexId]bool)
+	return countSimplePaths_helper(neighboursExtractor, from, to, 0, maxLen, nodesStatus)
+}
+
+func countSimplePaths_helper(neighboursExtractor OutNeighboursExtractor, from, to VertexId, pathPos, maxLen int, nodesStatus map[VertexId]bool) uint64 {
+	if _, ok := nodesStatus[from]; ok {
+		return 0
+	}
+	if from==to {
+		if pathPos>0 {
+			return 1
+		}
+		return 0
 	}
-	return
+	if maxLen>0 && pathPos>=maxLen {
+		// no more arcs allowed in this path
+		return 0
+	}
+	nodesStatus[from] = true
+	
+	var cnt uint64 = 0
+	for nextNode := range neighboursExtractor.GetOutNeighbours(from).VertexesIter() {
+		nextCnt := countSimplePaths_helper(neighboursExtractor, nextNode, to, pathPos+1, maxLen, nodesStatus)
+		if cnt > math.MaxUint64 - nextCnt {
+			cnt = math.MaxUint64
+		} else {
+			cnt += nextCnt
+		}
+	}
+	
+	nodesStatus[from] = false, false
+	
+	return cnt
 }
 
 func GetAllDirectedPaths(gr DirectedGraphArcsReader, from, to VertexId) <-chan []VertexId {
@@ -215,7 +1510,8 @@ func PathFromMarks(marks PathMarks, destination VertexId) Vertexes {
 // Returs map, contains all nodes from graph. If there is no path from source to node in map
 // then value for this node is math.MaxFloat64
 //
-// Returns nil if there are negative cycles. 
+// Returns nil if there are negative cycles. Self-loop with negative weight is
+// a negative cycle, other self-loops are ignored.
 func BellmanFordMultiSource(gr DirectedGraphReader, sources Vertexes, weightFunc ConnectionWeightFunc) PathMarks {
 	marks := make(PathMarks)
 	for vertex := range gr.VertexesIter() {
@@ -250,6 +1546,331 @@ func BellmanFordSingleSource(gr DirectedGraphReader, source VertexId, weightFunc
 	return BellmanFordMultiSource(gr, Vertexes{source}, weightFunc)
 }
 
+// Compute single-source shortest paths with Bellman-Ford algorithm
+//
+// Same as BellmanFordSingleSource, but result is split to distances map and
+// predecessors map. Distances map contains all nodes from graph, and if there
+// is no path from source to node, then distance is math.MaxFloat64.
+// Predecessors map contains previous node in shortest path for each node,
+// reachable from source, except source itself. Unreachable nodes are absent.
+//
+// Returns false if there are negative cycles.
+func BellmanFordSingleSourcePaths(gr DirectedGraphReader, source VertexId, weightFunc ConnectionWeightFunc) (map[VertexId]float64, map[VertexId]VertexId, bool) {
+	marks := BellmanFordSingleSource(gr, source, weightFunc)
+	if marks==nil {
+		return nil, nil, false
+	}
+	
+	dist := make(map[VertexId]float64, len(marks))
+	prev := make(map[VertexId]VertexId)
+	for node, mark := range marks {
+		dist[node] = mark.Weight
+		if node!=source && mark.Weight!=math.MaxFloat64 {
+			prev[node] = mark.PrevVertex
+		}
+	}
+	return dist, prev, true
+}
+
+// Shortest paths distances from each of sources with Bellman-Ford algorithm
+//
+// Returns distances maps: result[source][node] is exactly the same as
+// distance from BellmanFordSingleSourcePaths(gr, source, weightFunc). Search
+// from each source has it's own state, and weightFunc is the only shared
+// thing between searches.
+//
+// Returns nil if there are negative cycles.
+func MultiSourceShortestPaths(gr DirectedGraphReader, sources []VertexId, weightFunc ConnectionWeightFunc) map[VertexId]map[VertexId]float64 {
+	res := make(map[VertexId]map[VertexId]float64, len(sources))
+	for _, source := range sources {
+		if _, ok := res[source]; ok {
+			continue
+		}
+		dist, _, ok := BellmanFordSingleSourcePaths(gr, source, weightFunc)
+		if !ok {
+			return nil
+		}
+		res[source] = dist
+	}
+	return res
+}
+
+// Single-source shortest paths with SPFA (queue-based Bellman-Ford algorithm)
+//
+// Only accessors of nodes, which distance was changed, are relaxed again, so
+// on sparse graphs it's usually much faster than BellmanFordSingleSource,
+// while distances are the same. Distances map contains all nodes from graph,
+// and if there is no path from source to node, then distance is
+// math.MaxFloat64.
+//
+// Without negative cycles each node is queued less than V times, so node,
+// queued more than V times, means negative cycle, reachable from source, and
+// then (nil, false) is returned.
+func SPFA(gr DirectedGraphReader, source VertexId, weightFunc ConnectionWeightFunc) (map[VertexId]float64, bool) {
+	dist := make(map[VertexId]float64, gr.Order())
+	for node := range gr.VertexesIter() {
+		dist[node] = math.MaxFloat64
+	}
+	dist[source] = 0.0
+	
+	nodesCnt := gr.Order()
+	queued := map[VertexId]bool{source:true}
+	queuedCnt := map[VertexId]int{source:1}
+	queue := []VertexId{source}
+	negativeCycle := false
+	for len(queue)>0 && !negativeCycle {
+		curNode := queue[0]
+		queue = queue[1:]
+		queued[curNode] = false, false
+		// accessors channel is read to the end even if negative cycle is found
+		for nextNode := range gr.GetAccessors(curNode).VertexesIter() {
+			if negativeCycle {
+				continue
+			}
+			possibleWeight := dist[curNode] + weightFunc(curNode, nextNode)
+			if possibleWeight >= dist[nextNode] {
+				continue
+			}
+			dist[nextNode] = possibleWeight
+			if queued[nextNode] {
+				continue
+			}
+			queuedCnt[nextNode]++
+			if queuedCnt[nextNode]>nodesCnt {
+				negativeCycle = true
+				continue
+			}
+			queued[nextNode] = true
+			queue = append(queue, nextNode)
+		}
+	}
+	
+	if negativeCycle {
+		return nil, false
+	}
+	return dist, true
+}
+
+// Find negative cycle, reachable from source, with Bellman-Ford algorithm
+//
+// Returns cycle vertexes in order: there is an arc from each vertex to the
+// next one, and from the last vertex to the first one. Returns nil if there
+// are no negative cycles, reachable from source.
+func FindNegativeCycle(gr DirectedGraphReader, source VertexId, weightFunc ConnectionWeightFunc) []VertexId {
+	// path weights and previous nodes for all reached nodes
+	dist := make(map[VertexId]float64)
+	dist[source] = 0.0
+	prev := make(map[VertexId]VertexId)
+	
+	nodesCnt := gr.Order()
+	for i:=0; i<nodesCnt-1; i++ {
+		for conn := range gr.ArcsIter() {
+			tailWeight, ok := dist[conn.Tail]
+			if !ok {
+				continue
+			}
+			possibleWeight := tailWeight + weightFunc(conn.Tail, conn.Head)
+			if headWeight, ok := dist[conn.Head]; !ok || headWeight > possibleWeight {
+				dist[conn.Head] = possibleWeight
+				prev[conn.Head] = conn.Tail
+			}
+		}
+	}
+	
+	// if any arc still relaxes on the N-th pass, then there is a negative cycle
+	cycleFound := false
+	var cycleNode VertexId
+	for conn := range gr.ArcsIter() {
+		tailWeight, ok := dist[conn.Tail]
+		if !ok {
+			continue
+		}
+		if dist[conn.Head] > tailWeight + weightFunc(conn.Tail, conn.Head) {
+			prev[conn.Head] = conn.Tail
+			cycleNode = conn.Head
+			cycleFound = true
+			break
+		}
+	}
+	
+	if !cycleFound {
+		return nil
+	}
+	
+	// relaxed node may be not in cycle itself, but only reachable from it.
+	// N steps back are guaranteed to get inside the cycle.
+	for i:=0; i<nodesCnt; i++ {
+		cycleNode = prev[cycleNode]
+	}
+	
+	cycle := make([]VertexId, 0, 10)
+	cycle = append(cycle, cycleNode)
+	for curNode := prev[cycleNode]; curNode!=cycleNode; curNode = prev[curNode] {
+		cycle = append(cycle, curNode)
+	}
+	
+	// reversing cycle to follow arcs direction
+	cycleLen := len(cycle)
+	for i:=0; i<cycleLen/2; i++ {
+		cycle[i], cycle[cycleLen-i-1] = cycle[cycleLen-i-1], cycle[i]
+	}
+	
+	return cycle
+}
+
+// Compute all-pairs shortest paths with Floyd-Warshall algorithm
+//
+// Returns distances map: result[from][to] is shortest path weight from one
+// node to another. Map contains all pairs of nodes from graph, and if there is
+// no path between nodes, then distance is math.MaxFloat64.
+//
+// Returns nil if there are negative cycles. Distance from node to itself is 0,
+// so self-loop changes it only if loop weight is negative, and then it's a
+// negative cycle.
+func FloydWarshall(gr DirectedGraphReader, weightFunc ConnectionWeightFunc) map[VertexId]map[VertexId]float64 {
+	nodes := CollectVertexes(gr)
+	dist := make(map[VertexId]map[VertexId]float64, len(nodes))
+	for _, from := range nodes {
+		dist[from] = make(map[VertexId]float64, len(nodes))
+		for _, to := range nodes {
+			dist[from][to] = math.MaxFloat64
+		}
+		dist[from][from] = 0.0
+	}
+	
+	for conn := range gr.ArcsIter() {
+		arcWeight := weightFunc(conn.Tail, conn.Head)
+		if arcWeight < dist[conn.Tail][conn.Head] {
+			dist[conn.Tail][conn.Head] = arcWeight
+		}
+	}
+	
+	for _, middle := range nodes {
+		toMiddle := dist[middle]
+		for _, from := range nodes {
+			fromDist := dist[from]
+			if fromDist[middle]==math.MaxFloat64 {
+				continue
+			}
+			for _, to := range nodes {
+				if toMiddle[to]==math.MaxFloat64 {
+					continue
+				}
+				if possibleWeight := fromDist[middle] + toMiddle[to]; possibleWeight < fromDist[to] {
+					fromDist[to] = possibleWeight
+				}
+			}
+		}
+	}
+	
+	// checking for negative cycles
+	for _, node := range nodes {
+		if dist[node][node] < 0 {
+			return nil
+		}
+	}
+	
+	return dist
+}
+
+// Compute all-pairs shortest paths with parallel Dijkstra searches
+//
+// Result is exactly the same as in FloydWarshall: result[from][to] is shortest
+// path weight, and if there is no path between nodes, then distance is
+// math.MaxFloat64. Single source Dijkstra search is done for each node, and
+// searches are distributed among workers goroutines.
+//
+// Weights are calculated once before searches, so weightFunc is never called
+// concurrently. Negative weight causes panic.
+func AllPairsShortestPathsParallel(gr DirectedGraphReader, weightFunc ConnectionWeightFunc, workers int) map[VertexId]map[VertexId]float64 {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Compute all-pairs shortest paths with parallel Dijkstra searches", e)
+			err.AddV("workers", workers)
+			panic(err)
+		}
+	}()
+	
+	if workers<=0 {
+		err := erx.NewError("Workers count must be positive.")
+		err.AddV("workers", workers)
+		panic(err)
+	}
+	
+	nodes := CollectVertexes(gr)
+	arcs := make(map[VertexId][]WeightedVertex, len(nodes))
+	for conn := range gr.ArcsIter() {
+		arcWeight := weightFunc(conn.Tail, conn.Head)
+		if arcWeight < 0 {
+			err := erx.NewError("Negative weight detected")
+			err.AddV("head", conn.Tail)
+			err.AddV("tail", conn.Head)
+			err.AddV("weight", arcWeight)
+			panic(err)
+		}
+		arcs[conn.Tail] = append(arcs[conn.Tail], WeightedVertex{conn.Head, arcWeight})
+	}
+	
+	type sourceDistances struct {
+		from VertexId
+		dist map[VertexId]float64
+	}
+	
+	sources := make(chan VertexId, len(nodes))
+	for _, from := range nodes {
+		sources <- from
+	}
+	close(sources)
+	
+	results := make(chan sourceDistances, len(nodes))
+	for i:=0; i<workers; i++ {
+		go func() {
+			for from := range sources {
+				results <- sourceDistances{from, dijkstraDistances(arcs, nodes, from)}
+			}
+		}()
+	}
+	
+	dist := make(map[VertexId]map[VertexId]float64, len(nodes))
+	for i:=0; i<len(nodes); i++ {
+		res := <-results
+		dist[res.from] = res.dist
+	}
+	
+	return dist
+}
+
+// Shortest paths weights from one node to all nodes with Dijkstra algorithm
+//
+// arcs are read only, so it's safe to do several searches concurrently.
+// Distance to unreachable nodes is math.MaxFloat64.
+func dijkstraDistances(arcs map[VertexId][]WeightedVertex, nodes []VertexId, from VertexId) map[VertexId]float64 {
+	dist := make(map[VertexId]float64, len(nodes))
+	for _, node := range nodes {
+		dist[node] = math.MaxFloat64
+	}
+	dist[from] = 0.0
+	
+	q := NewPriorityQueue(10)
+	q.Push(from, 0.0)
+	settled := make(map[VertexId]bool)
+	for q.Len()>0 {
+		curNode, curWeight := q.Pop()
+		settled[curNode] = true
+		for _, next := range arcs[curNode] {
+			if _, ok := settled[next.Node]; ok {
+				continue
+			}
+			if nextWeight := curWeight + next.Weight; nextWeight < dist[next.Node] {
+				dist[next.Node] = nextWeight
+				q.Push(next.Node, nextWeight)
+			}
+		}
+	}
+	
+	return dist
+}
+
 // Compute multi-source shortest paths with Bellman-Ford algorithm
 //
 // Returs map, contains all accessiable vertexes from sources vertexes with
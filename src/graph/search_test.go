@@ -1,6 +1,8 @@
 package graph
 
 import (
+	"math"
+	"rand"
 	"testing"
 	"github.com/orfjackal/gospec/src/gospec"
 	. "github.com/orfjackal/gospec/src/gospec"
@@ -99,6 +101,554 @@ func GetAllMixedPathsSpec(c gospec.Context) {
 	c.Expect(pathsCnt, Equals, 4)
 }
 
+func GetAllPathsCancelableSpec(c gospec.Context) {
+	gr := generateMixedGraph1()
+	extractor := NewMgraphOutNeighboursExtractor(gr)
+	
+	c.Specify("Without stop all paths are found", func() {
+		done := make(chan bool)
+		pathsCnt := 0
+		for _ = range GetAllPathsCancelable(extractor, 1, 6, done) {
+			pathsCnt++
+		}
+		c.Expect(pathsCnt, Equals, 4)
+	})
+	
+	c.Specify("Channel is closed after stop", func() {
+		done := make(chan bool)
+		pathsCnt := 0
+		for _ = range GetAllPathsCancelable(extractor, 1, 6, done) {
+			pathsCnt++
+			if pathsCnt==2 {
+				close(done)
+			}
+		}
+		// generator may still send a path, if it's already waiting in select
+		// with both cases ready, but it stops before all 4 paths are sent
+		c.Expect(pathsCnt>=2, IsTrue)
+		c.Expect(pathsCnt<4, IsTrue)
+	})
+}
+
+func GetAllPathsMaxLenSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	extractor := NewDgraphOutNeighboursExtractor(gr)
+	
+	c.Specify("Only short paths are found", func() {
+		paths := make([][]VertexId, 0, 10)
+		for path := range GetAllPathsMaxLen(extractor, 1, 5, 3) {
+			paths = append(paths, path)
+		}
+		c.Expect(len(paths), Equals, 1)
+		c.Expect(paths[0], ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(4), VertexId(5)))
+	})
+	
+	c.Specify("Too short limit gives no paths", func() {
+		pathsCnt := 0
+		for _ = range GetAllPathsMaxLen(extractor, 1, 5, 2) {
+			pathsCnt++
+		}
+		c.Expect(pathsCnt, Equals, 0)
+	})
+	
+	c.Specify("Non-positive limit means unlimited", func() {
+		pathsCnt := 0
+		for _ = range GetAllPathsMaxLen(extractor, 1, 5, 0) {
+			pathsCnt++
+		}
+		c.Expect(pathsCnt, Equals, 2)
+	})
+}
+
+func CountSimplePathsSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	extractor := NewDgraphOutNeighboursExtractor(gr)
+	
+	c.Specify("Same count as in GetAllPaths", func() {
+		for from := range gr.VertexesIter() {
+			for to := range gr.VertexesIter() {
+				pathsCnt := uint64(0)
+				for _ = range GetAllPaths(extractor, from, to) {
+					pathsCnt++
+				}
+				c.Expect(CountSimplePaths(extractor, from, to), Equals, pathsCnt)
+			}
+		}
+	})
+	
+	c.Specify("Paths in complete graph", func() {
+		// paths between two nodes of K5 through any ordered subset of other 3 nodes
+		completeGr := CompleteGraph(5)
+		c.Expect(CountSimplePaths(NewUgraphOutNeighboursExtractor(completeGr), 0, 1), Equals, uint64(1 + 3 + 6 + 6))
+	})
+	
+	c.Specify("Max length", func() {
+		c.Expect(CountSimplePathsMaxLen(extractor, 1, 5, 3), Equals, uint64(1))
+		c.Expect(CountSimplePathsMaxLen(extractor, 1, 5, 2), Equals, uint64(0))
+	})
+}
+
+func KShortestPathsSpec(c gospec.Context) {
+	gr, weightFunc := generateWeightedDirectedGraph1()
+	extractor := NewDgraphOutNeighboursExtractor(gr)
+	
+	c.Specify("Top 3 paths in order of increasing weight", func() {
+		paths := KShortestPaths(extractor, 1, 6, 3, weightFunc)
+		c.Expect(len(paths), Equals, 3)
+		c.Expect(paths[0], ContainsInOrder, Values(VertexId(1), VertexId(3), VertexId(4), VertexId(6)))
+		c.Expect(paths[1], ContainsInOrder, Values(VertexId(1), VertexId(3), VertexId(5), VertexId(6)))
+		c.Expect(paths[2], ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(4), VertexId(6)))
+	})
+	
+	c.Specify("Fewer paths, than requested", func() {
+		gr := generateDirectedGraph1()
+		paths := KShortestPaths(NewDgraphOutNeighboursExtractor(gr), 1, 5, 10, SimpleWeightFunc)
+		c.Expect(len(paths), Equals, 2)
+		c.Expect(paths[0], ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(4), VertexId(5)))
+		c.Expect(paths[1], ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(3), VertexId(4), VertexId(5)))
+	})
+	
+	c.Specify("No path", func() {
+		paths := KShortestPaths(extractor, 6, 1, 3, weightFunc)
+		c.Expect(len(paths), Equals, 0)
+	})
+}
+
+func SecondShortestPathSpec(c gospec.Context) {
+	gr := NewDirectedMap()
+	ReadDgraphLine(gr, "1>2>3>4")
+	ReadDgraphLine(gr, "2>5>3")
+	ReadDgraphLine(gr, "3>6>3")
+	weightFunc := func(tail, head VertexId) float64 {
+		switch {
+			case tail==3 && head==6 || tail==6 && head==3:
+				return 0.5
+			case tail==5 && head==3:
+				return 2.0
+		}
+		return 1.0
+	}
+	extractor := NewDgraphOutNeighboursExtractor(gr)
+	
+	c.Specify("Simple second path", func() {
+		path, weight, ok := SecondShortestPath(extractor, 1, 4, true, weightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 5.0)
+		c.Expect(path, ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(5), VertexId(3), VertexId(4)))
+	})
+	
+	c.Specify("Second walk may revisit vertexes", func() {
+		path, weight, ok := SecondShortestPath(extractor, 1, 4, false, weightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 4.0)
+		c.Expect(path, ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(3), VertexId(6), VertexId(3), VertexId(4)))
+	})
+	
+	c.Specify("Only one path", func() {
+		line := NewDirectedMap()
+		ReadDgraphLine(line, "1>2>3")
+		lineExtractor := NewDgraphOutNeighboursExtractor(line)
+		path, _, ok := SecondShortestPath(lineExtractor, 1, 3, true, SimpleWeightFunc)
+		c.Expect(ok, IsFalse)
+		c.Expect(path, IsNil)
+		path, _, ok = SecondShortestPath(lineExtractor, 1, 3, false, SimpleWeightFunc)
+		c.Expect(ok, IsFalse)
+		c.Expect(path, IsNil)
+	})
+	
+	c.Specify("Cycle through from node", func() {
+		_, _, ok := SecondShortestPath(extractor, 3, 3, true, weightFunc)
+		c.Expect(ok, IsFalse)
+		path, weight, ok := SecondShortestPath(extractor, 3, 3, false, weightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 1.0)
+		c.Expect(path, ContainsInOrder, Values(VertexId(3), VertexId(6), VertexId(3)))
+	})
+}
+
+func WidestPathSpec(c gospec.Context) {
+	gr, capacityFunc := genWeightedDgraph(
+		weightedConnection{1, 2, 2.0},
+		weightedConnection{2, 5, 3.0},
+		weightedConnection{1, 3, 10.0},
+		weightedConnection{3, 4, 8.0},
+		weightedConnection{4, 5, 9.0},
+		weightedConnection{3, 5, 1.0},
+	)
+	extractor := NewDgraphOutNeighboursExtractor(gr)
+	
+	c.Specify("Widest path differs from the shortest", func() {
+		shortest, _, _ := ShortestPathDijkstra(extractor, 1, 5, nil, SimpleWeightFunc)
+		c.Expect(len(shortest), Equals, 3)
+		path, width, ok := WidestPath(extractor, 1, 5, capacityFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(width, Equals, 8.0)
+		c.Expect(path, ContainsInOrder, Values(VertexId(1), VertexId(3), VertexId(4), VertexId(5)))
+	})
+	
+	c.Specify("Single node path", func() {
+		path, width, ok := WidestPath(extractor, 2, 2, capacityFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(math.IsInf(width, 1), IsTrue)
+		c.Expect(path, ContainsExactly, Values(VertexId(2)))
+	})
+	
+	c.Specify("No path", func() {
+		path, _, ok := WidestPath(extractor, 5, 1, capacityFunc)
+		c.Expect(ok, IsFalse)
+		c.Expect(path, IsNil)
+	})
+}
+
+func FilteredNeighboursExtractorSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	
+	c.Specify("No path through excluded articulation node", func() {
+		extractor := NewFilteredNeighboursExtractor(NewDgraphOutNeighboursExtractor(gr), map[VertexId]bool{4:true})
+		_, ok := CheckPathDijkstra(extractor, 1, 5, nil, SimpleWeightFunc)
+		c.Expect(ok, IsFalse)
+	})
+	
+	c.Specify("Path around excluded node", func() {
+		extractor := NewFilteredNeighboursExtractor(NewDgraphOutNeighboursExtractor(gr), map[VertexId]bool{2:true})
+		path, _, ok := ShortestPathDijkstra(extractor, 1, 6, nil, SimpleWeightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(path, ContainsInOrder, Values(VertexId(1), VertexId(6)))
+		_, ok = CheckPathDijkstra(extractor, 1, 3, nil, SimpleWeightFunc)
+		c.Expect(ok, IsFalse)
+	})
+	
+	c.Specify("Excluded node has no neighbours", func() {
+		extractor := NewFilteredNeighboursExtractor(NewDgraphOutNeighboursExtractor(gr), map[VertexId]bool{2:true})
+		c.Expect(len(CollectVertexes(extractor.GetOutNeighbours(2))), Equals, 0)
+		c.Expect(CollectVertexes(extractor.GetOutNeighbours(1)), ContainsExactly, Values(VertexId(6)))
+	})
+}
+
+func SnapshotExtractorSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	extractor := NewSnapshotExtractor(NewDgraphOutNeighboursExtractor(gr), CollectVertexes(gr))
+	
+	c.Specify("Same neighbours as in graph", func() {
+		for node := range gr.VertexesIter() {
+			c.Expect(CollectVertexes(extractor.GetOutNeighbours(node)), ContainsExactly, CollectVertexes(gr.GetAccessors(node)))
+		}
+	})
+	
+	c.Specify("Same shortest path as in graph", func() {
+		path, weight, ok := ShortestPathDijkstra(extractor, 1, 5, nil, SimpleWeightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 3.0)
+		c.Expect(path, ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(4), VertexId(5)))
+	})
+	
+	c.Specify("Later graph changes aren't reflected", func() {
+		gr.AddArc(5, 1)
+		gr.RemoveArc(1, 6)
+		c.Expect(len(CollectVertexes(extractor.GetOutNeighbours(5))), Equals, 0)
+		c.Expect(CollectVertexes(extractor.GetOutNeighbours(1)), ContainsExactly, Values(VertexId(2), VertexId(6)))
+	})
+	
+	c.Specify("Vertexes not from the list haven't neighbours", func() {
+		partial := NewSnapshotExtractor(NewDgraphOutNeighboursExtractor(gr), []VertexId{1, 2})
+		c.Expect(CollectVertexes(partial.GetOutNeighbours(2)), ContainsExactly, Values(VertexId(3), VertexId(4), VertexId(6)))
+		c.Expect(len(CollectVertexes(partial.GetOutNeighbours(4))), Equals, 0)
+	})
+}
+
+func DijkstraSearcherSpec(c gospec.Context) {
+	gr := GridGraph(4, 5)
+	extractor := NewUgraphOutNeighboursExtractor(gr)
+	weightFunc := func(tail, head VertexId) float64 {
+		return float64((tail*7 + head*3) % 5)
+	}
+	searcher := NewDijkstraSearcher(extractor, weightFunc)
+	
+	c.Specify("Same results as CheckPathDijkstra on repeated queries", func() {
+		for from := range gr.VertexesIter() {
+			for to := range gr.VertexesIter() {
+				path, weight, ok := searcher.FindPath(from, to)
+				expectedWeight, _ := CheckPathDijkstra(extractor, from, to, nil, weightFunc)
+				c.Expect(ok, IsTrue)
+				c.Expect(weight, Equals, expectedWeight)
+				c.Expect(path[0], Equals, from)
+				c.Expect(path[len(path)-1], Equals, to)
+				pathWeight := 0.0
+				for i:=1; i<len(path); i++ {
+					pathWeight += weightFunc(path[i-1], path[i])
+				}
+				c.Expect(pathWeight, Equals, weight)
+			}
+		}
+	})
+	
+	c.Specify("No path after successful query", func() {
+		dgr := generateDirectedGraph1()
+		dsearcher := NewDijkstraSearcher(NewDgraphOutNeighboursExtractor(dgr), SimpleWeightFunc)
+		path, weight, ok := dsearcher.FindPath(1, 5)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 3.0)
+		c.Expect(path, ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(4), VertexId(5)))
+		path, _, ok = dsearcher.FindPath(5, 1)
+		c.Expect(ok, IsFalse)
+		c.Expect(path, IsNil)
+		_, weight, ok = dsearcher.FindPath(2, 5)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 2.0)
+	})
+}
+
+func NearestSourceSpec(c gospec.Context) {
+	c.Specify("Path with sources on both ends", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-4-5-6")
+		gr.AddNode(7)
+		nearest, dist := NearestSource(NewUgraphOutNeighboursExtractor(gr), []VertexId{6, 1})
+		c.Expect(len(nearest), Equals, 6)
+		c.Expect(len(dist), Equals, 6)
+		c.Expect(nearest[3], Equals, VertexId(1))
+		c.Expect(dist[3], Equals, 2)
+		c.Expect(nearest[4], Equals, VertexId(6))
+		c.Expect(dist[4], Equals, 2)
+		c.Expect(nearest[6], Equals, VertexId(6))
+		c.Expect(dist[6], Equals, 0)
+		_, ok := nearest[7]
+		c.Expect(ok, IsFalse)
+	})
+	
+	c.Specify("Ties break toward smaller source", func() {
+		// 5 is 2 connections from both sources, and 9 is found first
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "9-4-5-3-2")
+		for i:=0; i<5; i++ {
+			nearest, dist := NearestSource(NewUgraphOutNeighboursExtractor(gr), []VertexId{9, 2})
+			c.Expect(nearest[5], Equals, VertexId(2))
+			c.Expect(dist[5], Equals, 2)
+			c.Expect(nearest[4], Equals, VertexId(9))
+			c.Expect(nearest[3], Equals, VertexId(2))
+		}
+	})
+	
+	c.Specify("Each node has distance of BFS from nearest source", func() {
+		gr := GridGraph(5, 5)
+		extractor := NewUgraphOutNeighboursExtractor(gr)
+		sources := []VertexId{0, 12, 24}
+		nearest, dist := NearestSource(extractor, sources)
+		c.Expect(len(nearest), Equals, 25)
+		for node, source := range nearest {
+			path, _ := UnweightedShortestPath(extractor, source, node)
+			c.Expect(dist[node], Equals, len(path)-1)
+			for _, otherSource := range sources {
+				otherPath, _ := UnweightedShortestPath(extractor, otherSource, node)
+				c.Expect(len(otherPath) >= len(path), IsTrue)
+			}
+		}
+	})
+}
+
+func ZeroOneBFSSpec(c gospec.Context) {
+	gr := GridGraph(4, 5)
+	extractor := NewUgraphOutNeighboursExtractor(gr)
+	// moving along row is free, moving to other row costs 1
+	weightFunc := func(tail, head VertexId) float64 {
+		if tail/5==head/5 {
+			return 0.0
+		}
+		return 1.0
+	}
+	
+	c.Specify("Same weights as Dijkstra search", func() {
+		for from := range gr.VertexesIter() {
+			for to := range gr.VertexesIter() {
+				weight, ok := ZeroOneBFS(extractor, from, to, weightFunc)
+				expectedWeight, _ := CheckPathDijkstra(extractor, from, to, nil, weightFunc)
+				c.Expect(ok, IsTrue)
+				c.Expect(weight, Equals, expectedWeight)
+			}
+		}
+		weight, _ := ZeroOneBFS(extractor, 0, 19, weightFunc)
+		c.Expect(weight, Equals, 3.0)
+	})
+	
+	c.Specify("No path", func() {
+		_, ok := ZeroOneBFS(NewDgraphOutNeighboursExtractor(generateDirectedGraph1()), 5, 1, SimpleWeightFunc)
+		c.Expect(ok, IsFalse)
+	})
+	
+	c.Specify("Other weight causes panic", func() {
+		defer func() {
+			c.Expect(recover(), Not(IsNil))
+		}()
+		ZeroOneBFS(extractor, 0, 19, ConstantWeight(2.0))
+	})
+}
+
+func BenchmarkCheckPathDijkstra(b *testing.B) {
+	gr := GridGraph(20, 20)
+	extractor := NewUgraphOutNeighboursExtractor(gr)
+	for i:=0; i<b.N; i++ {
+		CheckPathDijkstra(extractor, VertexId(i%400), VertexId(399 - i%400), nil, SimpleWeightFunc)
+	}
+}
+
+func BenchmarkDijkstraSearcher(b *testing.B) {
+	gr := GridGraph(20, 20)
+	searcher := NewDijkstraSearcher(NewUgraphOutNeighboursExtractor(gr), SimpleWeightFunc)
+	for i:=0; i<b.N; i++ {
+		searcher.FindPath(VertexId(i%400), VertexId(399 - i%400))
+	}
+}
+
+func WeightCombinatorsSpec(c gospec.Context) {
+	distance := func(tail, head VertexId) float64 {
+		return float64(head - tail)
+	}
+	
+	c.Specify("Combined weights", func() {
+		c.Expect(ConstantWeight(2.5)(1, 7), Equals, 2.5)
+		c.Expect(ConstantWeight(1.0)(3, 4), Equals, SimpleWeightFunc(3, 4))
+		c.Expect(ScaleWeight(distance, 3.0)(1, 3), Equals, 6.0)
+		c.Expect(AddWeights(distance, ConstantWeight(0.5))(2, 6), Equals, 4.5)
+	})
+	
+	c.Specify("Composite cost in search", func() {
+		// 1>2>4>5 has 3 arcs with distance 4, 1>2>3>4>5 has 4 arcs with the same distance
+		gr := generateDirectedGraph1()
+		path, weight, ok := ShortestPathDijkstra(NewDgraphOutNeighboursExtractor(gr), 1, 5, nil, AddWeights(distance, ConstantWeight(10.0)))
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 34.0)
+		c.Expect(path, ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(4), VertexId(5)))
+	})
+	
+	c.Specify("Memoized weight is calculated once", func() {
+		calls := make(map[VertexId]map[VertexId]int)
+		countingDistance := func(tail, head VertexId) float64 {
+			if _, ok := calls[tail]; !ok {
+				calls[tail] = make(map[VertexId]int)
+			}
+			calls[tail][head]++
+			return distance(tail, head)
+		}
+		weightFunc := MemoizeWeight(countingDistance)
+		gr := generateDirectedGraph1()
+		for i:=0; i<3; i++ {
+			weight, ok := CheckPathDijkstra(NewDgraphOutNeighboursExtractor(gr), 1, 5, nil, weightFunc)
+			c.Expect(ok, IsTrue)
+			c.Expect(weight, Equals, 4.0)
+		}
+		c.Expect(weightFunc(3, 6), Equals, 3.0)
+		c.Expect(weightFunc(3, 6), Equals, 3.0)
+		for _, heads := range calls {
+			for _, cnt := range heads {
+				c.Expect(cnt, Equals, 1)
+			}
+		}
+	})
+	
+	c.Specify("Negative factor causes panic in Dijkstra search", func() {
+		gr := generateDirectedGraph1()
+		defer func() {
+			c.Expect(recover(), Not(IsNil))
+		}()
+		CheckPathDijkstra(NewDgraphOutNeighboursExtractor(gr), 1, 5, nil, ScaleWeight(distance, -1.0))
+	})
+}
+
+type mapWeightedNeighboursExtractor map[VertexId][]WeightedVertex
+
+func (e mapWeightedNeighboursExtractor) GetOutNeighboursWeighted(node VertexId) []WeightedVertex {
+	return e[node]
+}
+
+func CheckPathDijkstraSpec(c gospec.Context) {
+	gr := NewDirectedMap()
+	ReadDgraphLine(gr, "1>3")
+	ReadDgraphLine(gr, "1>2>4>3")
+	extractor := NewDgraphOutNeighboursExtractor(gr)
+	// direct arc is discovered first, but it's heavier than path through 2 and 4
+	weightFunc := func(tail, head VertexId) float64 {
+		if tail==1 && head==3 {
+			return 10.0
+		}
+		return 1.0
+	}
+	
+	c.Specify("Minimal weight of two paths", func() {
+		weight, ok := CheckPathDijkstra(extractor, 1, 3, nil, weightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 3.0)
+	})
+	
+	c.Specify("Heavy path is cut by stop function", func() {
+		stopFunc := func(node VertexId, sumWeight float64) bool {
+			return sumWeight > 5.0
+		}
+		weight, ok := CheckPathDijkstra(extractor, 1, 3, stopFunc, weightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 3.0)
+	})
+	
+	c.Specify("Stop function isn't called for to node", func() {
+		stopFunc := func(node VertexId, sumWeight float64) bool {
+			return node==3
+		}
+		weight, ok := CheckPathDijkstra(extractor, 1, 3, stopFunc, weightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 3.0)
+	})
+	
+	c.Specify("Weights of arcs to settled nodes aren't calculated", func() {
+		gr.AddArc(2, 2)
+		gr.AddArc(2, 1)
+		checkedWeightFunc := func(tail, head VertexId) float64 {
+			if head==tail || head==1 {
+				panic("Weight of arc to settled node is calculated.")
+			}
+			return weightFunc(tail, head)
+		}
+		weight, ok := CheckPathDijkstra(extractor, 1, 3, nil, checkedWeightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 3.0)
+	})
+}
+
+func CheckPathDijkstraWeightedSpec(c gospec.Context) {
+	c.Specify("Precomputed weights", func() {
+		extractor := mapWeightedNeighboursExtractor{
+			1: []WeightedVertex{WeightedVertex{2, 1.0}, WeightedVertex{4, 5.0}},
+			2: []WeightedVertex{WeightedVertex{3, 1.0}},
+			4: []WeightedVertex{WeightedVertex{3, 1.0}},
+		}
+		weight, ok := CheckPathDijkstraWeighted(extractor, 1, 3, nil)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 2.0)
+		_, ok = CheckPathDijkstraWeighted(extractor, 3, 1, nil)
+		c.Expect(ok, IsFalse)
+	})
+	
+	c.Specify("Greedy first path to destination isn't the shortest", func() {
+		// 3 is touched from 1 with weight 10 before 1->2->4->3 with weight 3
+		// is discovered
+		extractor := mapWeightedNeighboursExtractor{
+			1: []WeightedVertex{WeightedVertex{3, 10.0}, WeightedVertex{2, 1.0}},
+			2: []WeightedVertex{WeightedVertex{4, 1.0}},
+			4: []WeightedVertex{WeightedVertex{3, 1.0}},
+		}
+		weight, ok := CheckPathDijkstraWeighted(extractor, 1, 3, nil)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 3.0)
+	})
+	
+	c.Specify("Weights from weight function", func() {
+		gr := generateDirectedGraph1()
+		extractor := NewWeightedNeighboursExtractor(NewDgraphOutNeighboursExtractor(gr), SimpleWeightFunc)
+		c.Expect(len(extractor.GetOutNeighboursWeighted(2)), Equals, 3)
+		weight, ok := CheckPathDijkstraWeighted(extractor, 1, 5, nil)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 3.0)
+	})
+}
+
 func BellmanFordSingleSourceSpec(c gospec.Context) {
 	gr := generateDirectedGraph1()
 	
@@ -110,6 +660,534 @@ func BellmanFordSingleSourceSpec(c gospec.Context) {
 	c.Expect(PathFromMarks(marks, VertexId(1)), ContainsExactly, Values())
 }
 
+func BellmanFordSingleSourcePathsSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	
+	dist, prev, ok := BellmanFordSingleSourcePaths(gr, VertexId(2), SimpleWeightFunc)
+	c.Expect(ok, IsTrue)
+	c.Expect(len(dist), Equals, gr.Order())
+	c.Expect(dist[VertexId(5)], Equals, 2.0)
+	c.Expect(dist[VertexId(1)], Equals, math.MaxFloat64)
+	
+	c.Expect(len(prev), Equals, 4)
+	_, ok = prev[VertexId(1)]
+	c.Expect(ok, IsFalse)
+	_, ok = prev[VertexId(2)]
+	c.Expect(ok, IsFalse)
+	c.Expect(pathFromPredecessors(prev, 2, 5), ContainsInOrder, Values(VertexId(2), VertexId(4), VertexId(5)))
+	c.Expect(pathFromPredecessors(prev, 2, 6), ContainsInOrder, Values(VertexId(2), VertexId(6)))
+}
+
+func MultiSourceShortestPathsSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	weightFunc := func(tail, head VertexId) float64 {
+		return float64(head) - float64(tail)
+	}
+	
+	c.Specify("Same distances as Bellman-Ford for each source", func() {
+		res := MultiSourceShortestPaths(gr, []VertexId{1, 4, 6, 4}, weightFunc)
+		c.Expect(len(res), Equals, 3)
+		for source, dist := range res {
+			marks := BellmanFordSingleSource(gr, source, weightFunc)
+			c.Expect(len(dist), Equals, len(marks))
+			for node, mark := range marks {
+				c.Expect(dist[node], Equals, mark.Weight)
+			}
+		}
+		c.Expect(res[1][5], Equals, 4.0)
+		c.Expect(res[4][1], Equals, math.MaxFloat64)
+	})
+	
+	c.Specify("Distance maps are independent", func() {
+		res := MultiSourceShortestPaths(gr, []VertexId{1, 2}, weightFunc)
+		res[1][5] = 0.0
+		c.Expect(res[2][5], Equals, 3.0)
+	})
+	
+	c.Specify("Negative cycle", func() {
+		gr.AddArc(5, 2)
+		c.Expect(MultiSourceShortestPaths(gr, []VertexId{1}, ConstantWeight(-1.0)), IsNil)
+	})
+}
+
+func SPFASpec(c gospec.Context) {
+	c.Specify("Same distances as Bellman-Ford", func() {
+		gr := generateDirectedGraph1()
+		gr.AddArc(5, 3)
+		gr.AddNode(7)
+		weightFunc := func(tail, head VertexId) float64 {
+			if tail==2 && head==4 {
+				return -2.0
+			}
+			return float64(head) - float64(tail)
+		}
+		for _, source := range []VertexId{1, 2, 5, 7} {
+			dist, ok := SPFA(gr, source, weightFunc)
+			expectedDist, _, expectedOk := BellmanFordSingleSourcePaths(gr, source, weightFunc)
+			c.Expect(ok, Equals, expectedOk)
+			c.Expect(len(dist), Equals, gr.Order())
+			for node, weight := range expectedDist {
+				c.Expect(dist[node], Equals, weight)
+			}
+		}
+		dist, _ := SPFA(gr, 2, weightFunc)
+		c.Expect(dist[5], Equals, -1.0)
+		c.Expect(dist[1], Equals, math.MaxFloat64)
+	})
+	
+	c.Specify("Negative cycle", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "0>1>2>3>1")
+		ReadDgraphLine(gr, "3>4")
+		weightFunc := func(tail, head VertexId) float64 {
+			if tail==2 && head==3 {
+				return -3.0
+			}
+			return 1.0
+		}
+		dist, ok := SPFA(gr, 0, weightFunc)
+		c.Expect(ok, IsFalse)
+		c.Expect(dist, IsNil)
+		
+		c.Specify("isn't reachable from sink", func() {
+			dist, ok := SPFA(gr, 4, weightFunc)
+			c.Expect(ok, IsTrue)
+			c.Expect(dist[4], Equals, 0.0)
+		})
+	})
+}
+
+func FindNegativeCycleSpec(c gospec.Context) {
+	c.Specify("No negative cycles", func() {
+		gr := generateDirectedGraph1()
+		c.Expect(FindNegativeCycle(gr, VertexId(1), SimpleWeightFunc), IsNil)
+	})
+	
+	c.Specify("Negative triangle", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "0>1>2>3>1")
+		ReadDgraphLine(gr, "3>4")
+		weightFunc := func(tail, head VertexId) float64 {
+			if tail==2 && head==3 {
+				return -3.0
+			}
+			return 1.0
+		}
+		
+		cycle := FindNegativeCycle(gr, VertexId(0), weightFunc)
+		c.Expect(cycle, ContainsExactly, Values(VertexId(1), VertexId(2), VertexId(3)))
+		c.Expect(ContainDirectedPath(gr, append(cycle, cycle[0]), true), IsTrue)
+		
+		c.Specify("isn't reachable from sink", func() {
+			c.Expect(FindNegativeCycle(gr, VertexId(4), weightFunc), IsNil)
+		})
+	})
+}
+
+func FloydWarshallSpec(c gospec.Context) {
+	c.Specify("Agrees with Bellman-Ford", func() {
+		gr := generateDirectedGraph1()
+		weightFunc := func(tail, head VertexId) float64 {
+			return float64(tail + head)
+		}
+		dist := FloydWarshall(gr, weightFunc)
+		c.Expect(len(dist), Equals, gr.Order())
+		for from := range gr.VertexesIter() {
+			marks := BellmanFordSingleSource(gr, from, weightFunc)
+			for to, mark := range marks {
+				c.Expect(dist[from][to], Equals, mark.Weight)
+			}
+		}
+	})
+	
+	c.Specify("Negative cycle", func() {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>3>1")
+		weightFunc := func(tail, head VertexId) float64 {
+			if tail==3 {
+				return -3.0
+			}
+			return 1.0
+		}
+		c.Expect(FloydWarshall(gr, weightFunc), IsNil)
+	})
+}
+
+func AllPairsShortestPathsParallelSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	ReadDgraphLine(gr, "5>1")
+	gr.AddNode(7)
+	weightFunc := func(tail, head VertexId) float64 {
+		return float64(tail + head)
+	}
+	expected := FloydWarshall(gr, weightFunc)
+	
+	c.Specify("Same as Floyd-Warshall", func() {
+		for _, workers := range []int{1, 3, 16} {
+			dist := AllPairsShortestPathsParallel(gr, weightFunc, workers)
+			c.Expect(len(dist), Equals, len(expected))
+			for from, expectedDist := range expected {
+				c.Expect(len(dist[from]), Equals, len(expectedDist))
+				for to, weight := range expectedDist {
+					c.Expect(dist[from][to], Equals, weight)
+				}
+			}
+		}
+	})
+	
+	c.Specify("Unreachable node", func() {
+		dist := AllPairsShortestPathsParallel(gr, weightFunc, 2)
+		c.Expect(dist[1][7], Equals, math.MaxFloat64)
+		c.Expect(dist[7][7], Equals, 0.0)
+	})
+}
+
+func IterativeDeepeningPathSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	extractor := NewDgraphOutNeighboursExtractor(gr)
+	
+	c.Specify("Path to self", func() {
+		path, ok := IterativeDeepeningPath(extractor, 3, 3, 0)
+		c.Expect(ok, IsTrue)
+		c.Expect(path, ContainsInOrder, Values(VertexId(3)))
+	})
+	
+	c.Specify("Path with minimal connections count", func() {
+		path, ok := IterativeDeepeningPath(extractor, 1, 5, 10)
+		c.Expect(ok, IsTrue)
+		c.Expect(path, ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(4), VertexId(5)))
+	})
+	
+	c.Specify("Depth limit is too small", func() {
+		_, ok := IterativeDeepeningPath(extractor, 1, 5, 2)
+		c.Expect(ok, IsFalse)
+		_, ok = IterativeDeepeningPath(extractor, 1, 5, 3)
+		c.Expect(ok, IsTrue)
+	})
+	
+	c.Specify("No path in graph with cycle", func() {
+		cycleGr := NewDirectedMap()
+		ReadDgraphLine(cycleGr, "1>2>3>1")
+		cycleGr.AddNode(4)
+		_, ok := IterativeDeepeningPath(NewDgraphOutNeighboursExtractor(cycleGr), 1, 4, 10)
+		c.Expect(ok, IsFalse)
+	})
+}
+
+func ShortestPathDijkstraSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	extractor := NewDgraphOutNeighboursExtractor(gr)
+	
+	c.Specify("Path to self", func() {
+		path, weight, ok := ShortestPathDijkstra(extractor, 1, 1, nil, SimpleWeightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 0.0)
+		c.Expect(path, ContainsInOrder, Values(VertexId(1)))
+	})
+	
+	c.Specify("Shortest of several paths", func() {
+		path, weight, ok := ShortestPathDijkstra(extractor, 1, 5, nil, SimpleWeightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 3.0)
+		c.Expect(path, ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(4), VertexId(5)))
+	})
+	
+	c.Specify("Path with custom weights", func() {
+		weightFunc := func(tail, head VertexId) float64 {
+			if tail==2 && head==4 {
+				return 10.0
+			}
+			return 1.0
+		}
+		path, weight, ok := ShortestPathDijkstra(extractor, 1, 5, nil, weightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 4.0)
+		c.Expect(path, ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(3), VertexId(4), VertexId(5)))
+	})
+	
+	c.Specify("No path", func() {
+		path, _, ok := ShortestPathDijkstra(extractor, 5, 1, nil, SimpleWeightFunc)
+		c.Expect(ok, IsFalse)
+		c.Expect(path, IsNil)
+	})
+}
+
+func ShortestPathDetailedSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	extractor := NewDgraphOutNeighboursExtractor(gr)
+	
+	c.Specify("Path to self", func() {
+		path, edgeWeights, total, ok := ShortestPathDetailed(extractor, 1, 1, SimpleWeightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(total, Equals, 0.0)
+		c.Expect(path, ContainsInOrder, Values(VertexId(1)))
+		c.Expect(len(edgeWeights), Equals, 0)
+	})
+	
+	c.Specify("Arcs weights along path", func() {
+		weightFunc := func(tail, head VertexId) float64 {
+			return float64(tail)
+		}
+		path, edgeWeights, total, ok := ShortestPathDetailed(extractor, 1, 5, weightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(path, ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(4), VertexId(5)))
+		c.Expect(len(edgeWeights), Equals, len(path)-1)
+		c.Expect(edgeWeights, ContainsInOrder, Values(1.0, 2.0, 4.0))
+		c.Expect(total, Equals, 7.0)
+	})
+	
+	c.Specify("No path", func() {
+		path, edgeWeights, _, ok := ShortestPathDetailed(extractor, 5, 1, SimpleWeightFunc)
+		c.Expect(ok, IsFalse)
+		c.Expect(path, IsNil)
+		c.Expect(edgeWeights, IsNil)
+	})
+}
+
+func ShortestPathDijkstraPathStopSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	extractor := NewDgraphOutNeighboursExtractor(gr)
+	
+	c.Specify("Without stop function", func() {
+		path, weight, ok := ShortestPathDijkstraPathStop(extractor, 1, 5, nil, SimpleWeightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 3.0)
+		c.Expect(path, ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(4), VertexId(5)))
+	})
+	
+	c.Specify("Stop function gets tentative path", func() {
+		stopFunc := func(path []VertexId, sumWeight float64) bool {
+			c.Expect(path[0], Equals, VertexId(1))
+			c.Expect(float64(len(path)-1), Equals, sumWeight)
+			// reject paths, which go through 2 directly to 4
+			for i:=1; i<len(path); i++ {
+				if path[i-1]==2 && path[i]==4 {
+					return true
+				}
+			}
+			return false
+		}
+		path, weight, ok := ShortestPathDijkstraPathStop(extractor, 1, 5, stopFunc, SimpleWeightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 4.0)
+		c.Expect(path, ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(3), VertexId(4), VertexId(5)))
+	})
+	
+	c.Specify("All paths are cut", func() {
+		stopFunc := func(path []VertexId, sumWeight float64) bool {
+			return len(path)>3
+		}
+		_, _, ok := ShortestPathDijkstraPathStop(extractor, 1, 5, stopFunc, SimpleWeightFunc)
+		c.Expect(ok, IsFalse)
+	})
+}
+
+func ShortestPathAvoidingSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	extractor := NewDgraphOutNeighboursExtractor(gr)
+	
+	c.Specify("Path around avoided vertex", func() {
+		path, weight, ok := ShortestPathAvoiding(extractor, 1, 5, map[VertexId]bool{4:false}, SimpleWeightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 3.0)
+		c.Expect(path, ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(4), VertexId(5)))
+		// 2 is avoided, so path goes through 3 and 6
+		avoidGr := NewDirectedMap()
+		ReadDgraphLine(avoidGr, "1>2>4>5")
+		ReadDgraphLine(avoidGr, "1>3>6>4")
+		path, weight, ok = ShortestPathAvoiding(NewDgraphOutNeighboursExtractor(avoidGr), 1, 5, map[VertexId]bool{2:true}, SimpleWeightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 4.0)
+		c.Expect(path, ContainsInOrder, Values(VertexId(1), VertexId(3), VertexId(6), VertexId(4), VertexId(5)))
+	})
+	
+	c.Specify("No path when all paths are avoided", func() {
+		path, _, ok := ShortestPathAvoiding(extractor, 1, 5, map[VertexId]bool{4:true}, SimpleWeightFunc)
+		c.Expect(ok, IsFalse)
+		c.Expect(path, IsNil)
+	})
+	
+	c.Specify("Avoided endpoint", func() {
+		_, _, ok := ShortestPathAvoiding(extractor, 1, 5, map[VertexId]bool{5:true}, SimpleWeightFunc)
+		c.Expect(ok, IsFalse)
+		_, _, ok = ShortestPathAvoiding(extractor, 1, 1, map[VertexId]bool{1:true}, SimpleWeightFunc)
+		c.Expect(ok, IsFalse)
+		path, _, ok := ShortestPathAvoiding(extractor, 1, 1, nil, SimpleWeightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(path, ContainsExactly, Values(VertexId(1)))
+	})
+}
+
+func AllShortestPathsSpec(c gospec.Context) {
+	c.Specify("All paths in grid", func() {
+		// 0 1 2
+		// 3 4 5
+		extractor := NewUgraphOutNeighboursExtractor(GridGraph(2, 3))
+		paths, weight, ok := AllShortestPaths(extractor, 0, 5, SimpleWeightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 3.0)
+		c.Expect(len(paths), Equals, 3)
+		c.Expect(paths[0], ContainsInOrder, Values(VertexId(0), VertexId(1), VertexId(2), VertexId(5)))
+		c.Expect(paths[1], ContainsInOrder, Values(VertexId(0), VertexId(1), VertexId(4), VertexId(5)))
+		c.Expect(paths[2], ContainsInOrder, Values(VertexId(0), VertexId(3), VertexId(4), VertexId(5)))
+	})
+	
+	c.Specify("Only shortest paths", func() {
+		extractor := NewDgraphOutNeighboursExtractor(generateDirectedGraph1())
+		paths, weight, ok := AllShortestPaths(extractor, 1, 5, SimpleWeightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 3.0)
+		c.Expect(len(paths), Equals, 1)
+		c.Expect(paths[0], ContainsInOrder, Values(VertexId(1), VertexId(2), VertexId(4), VertexId(5)))
+	})
+	
+	c.Specify("Paths limit", func() {
+		extractor := NewUgraphOutNeighboursExtractor(GridGraph(4, 4))
+		paths, _, ok := AllShortestPathsLimit(extractor, 0, 15, SimpleWeightFunc, 5)
+		c.Expect(ok, IsTrue)
+		c.Expect(len(paths), Equals, 5)
+		paths, _, _ = AllShortestPaths(extractor, 0, 15, SimpleWeightFunc)
+		// binomial(6, 3)
+		c.Expect(len(paths), Equals, 20)
+	})
+	
+	c.Specify("No path", func() {
+		extractor := NewDgraphOutNeighboursExtractor(generateDirectedGraph1())
+		paths, _, ok := AllShortestPaths(extractor, 5, 1, SimpleWeightFunc)
+		c.Expect(ok, IsFalse)
+		c.Expect(paths, IsNil)
+	})
+}
+
+func AStarPathSpec(c gospec.Context) {
+	// 3x3 grid with vertex id = 3*row + col
+	gr := NewUndirectedMap()
+	for row:=0; row<3; row++ {
+		for col:=0; col<3; col++ {
+			node := VertexId(3*row + col)
+			if col<2 {
+				gr.AddEdge(node, node+1)
+			}
+			if row<2 {
+				gr.AddEdge(node, node+3)
+			}
+		}
+	}
+	extractor := NewUgraphOutNeighboursExtractor(gr)
+	manhattan := func(node VertexId) float64 {
+		row, col := int(node)/3, int(node)%3
+		return float64((2-row) + (2-col))
+	}
+	
+	c.Specify("Path with admissible heuristic", func() {
+		path, weight, ok := AStarPath(extractor, 0, 8, manhattan, SimpleWeightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 4.0)
+		c.Expect(len(path), Equals, 5)
+		c.Expect(ContainUndirectedPath(gr, path, true), IsTrue)
+	})
+	
+	c.Specify("Zero heuristic gives the same weight as Dijkstra", func() {
+		zero := func(node VertexId) float64 {
+			return 0.0
+		}
+		_, weight, ok := AStarPath(extractor, 2, 6, zero, SimpleWeightFunc)
+		dijkstraWeight, _ := CheckPathDijkstra(extractor, 2, 6, nil, SimpleWeightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, dijkstraWeight)
+	})
+	
+	c.Specify("No path", func() {
+		gr.AddNode(9)
+		path, _, ok := AStarPath(extractor, 0, 9, manhattan, SimpleWeightFunc)
+		c.Expect(ok, IsFalse)
+		c.Expect(path, IsNil)
+	})
+}
+
+func BidirectionalPathDijkstraSpec(c gospec.Context) {
+	weightFunc := func(tail, head VertexId) float64 {
+		return float64((7*int(tail) + 13*int(head)) % 10 + 1)
+	}
+	
+	c.Specify("Path to self", func() {
+		gr := generateDirectedGraph1()
+		weight, ok := BidirectionalPathDijkstra(NewDgraphOutNeighboursExtractor(gr), NewDgraphInNeighboursExtractor(gr), 2, 2, weightFunc)
+		c.Expect(ok, IsTrue)
+		c.Expect(weight, Equals, 0.0)
+	})
+	
+	c.Specify("No path", func() {
+		gr := generateDirectedGraph1()
+		_, ok := BidirectionalPathDijkstra(NewDgraphOutNeighboursExtractor(gr), NewDgraphInNeighboursExtractor(gr), 5, 1, weightFunc)
+		c.Expect(ok, IsFalse)
+	})
+	
+	c.Specify("Agrees with Dijkstra on random directed graphs", func() {
+		rng := rand.New(rand.NewSource(42))
+		for i:=0; i<10; i++ {
+			gr := NewDirectedMap()
+			nodesCnt := 30
+			for node:=0; node<nodesCnt; node++ {
+				gr.AddNode(VertexId(node))
+			}
+			for tail:=0; tail<nodesCnt; tail++ {
+				for head:=0; head<nodesCnt; head++ {
+					if tail!=head && rng.Float64() < 0.08 {
+						gr.AddArc(VertexId(tail), VertexId(head))
+					}
+				}
+			}
+			forward := NewDgraphOutNeighboursExtractor(gr)
+			backward := NewDgraphInNeighboursExtractor(gr)
+			for j:=0; j<10; j++ {
+				from := VertexId(rng.Intn(nodesCnt))
+				to := VertexId(rng.Intn(nodesCnt))
+				weight, ok := BidirectionalPathDijkstra(forward, backward, from, to, weightFunc)
+				_, expectedWeight, expectedOk := ShortestPathDijkstra(forward, from, to, nil, weightFunc)
+				c.Expect(ok, Equals, expectedOk)
+				c.Expect(weight, Equals, expectedWeight)
+				
+				unitWeight, _ := BidirectionalPathDijkstra(forward, backward, from, to, SimpleWeightFunc)
+				expectedUnitWeight, _ := CheckPathDijkstra(forward, from, to, nil, SimpleWeightFunc)
+				c.Expect(unitWeight, Equals, expectedUnitWeight)
+			}
+		}
+	})
+}
+
+func UnweightedShortestPathSpec(c gospec.Context) {
+	gr := generateMixedGraph1()
+	extractor := NewMgraphOutNeighboursExtractor(gr)
+	
+	c.Specify("Path to self", func() {
+		path, ok := UnweightedShortestPath(extractor, 3, 3)
+		c.Expect(ok, IsTrue)
+		c.Expect(path, ContainsInOrder, Values(VertexId(3)))
+	})
+	
+	c.Specify("Path with minimal connections count", func() {
+		path, ok := UnweightedShortestPath(extractor, 1, 5)
+		c.Expect(ok, IsTrue)
+		c.Expect(len(path), Equals, 4)
+		c.Expect(path[0], Equals, VertexId(1))
+		c.Expect(path[3], Equals, VertexId(5))
+		c.Expect(ContainMixedPath(gr, path, true), IsTrue)
+	})
+	
+	c.Specify("Path through undirected edge", func() {
+		path, ok := UnweightedShortestPath(extractor, 6, 5)
+		c.Expect(ok, IsTrue)
+		c.Expect(path, ContainsInOrder, Values(VertexId(6), VertexId(4), VertexId(5)))
+	})
+	
+	c.Specify("No path", func() {
+		path, ok := UnweightedShortestPath(extractor, 5, 1)
+		c.Expect(ok, IsFalse)
+		c.Expect(path, IsNil)
+	})
+}
+
 func TestSearch(t *testing.T) {
 	r := gospec.NewRunner()
 
@@ -133,7 +1211,36 @@ func TestSearch(t *testing.T) {
 	}
 	
 	r.AddSpec(GetAllMixedPathsSpec)
+	r.AddSpec(GetAllPathsCancelableSpec)
+	r.AddSpec(GetAllPathsMaxLenSpec)
+	r.AddSpec(CountSimplePathsSpec)
+	r.AddSpec(KShortestPathsSpec)
+	r.AddSpec(FilteredNeighboursExtractorSpec)
+	r.AddSpec(SnapshotExtractorSpec)
+	r.AddSpec(DijkstraSearcherSpec)
+	r.AddSpec(WeightCombinatorsSpec)
+	r.AddSpec(ZeroOneBFSSpec)
+	r.AddSpec(NearestSourceSpec)
+	r.AddSpec(CheckPathDijkstraSpec)
+	r.AddSpec(CheckPathDijkstraWeightedSpec)
 	r.AddSpec(BellmanFordSingleSourceSpec)
+	r.AddSpec(SPFASpec)
+	r.AddSpec(MultiSourceShortestPathsSpec)
+	r.AddSpec(BellmanFordSingleSourcePathsSpec)
+	r.AddSpec(FindNegativeCycleSpec)
+	r.AddSpec(FloydWarshallSpec)
+	r.AddSpec(AllPairsShortestPathsParallelSpec)
+	r.AddSpec(ShortestPathDijkstraSpec)
+	r.AddSpec(ShortestPathDetailedSpec)
+	r.AddSpec(ShortestPathAvoidingSpec)
+	r.AddSpec(SecondShortestPathSpec)
+	r.AddSpec(WidestPathSpec)
+	r.AddSpec(ShortestPathDijkstraPathStopSpec)
+	r.AddSpec(AllShortestPathsSpec)
+	r.AddSpec(AStarPathSpec)
+	r.AddSpec(BidirectionalPathDijkstraSpec)
+	r.AddSpec(UnweightedShortestPathSpec)
+	r.AddSpec(IterativeDeepeningPathSpec)
 
 
 	gospec.MainGoTest(r, t)
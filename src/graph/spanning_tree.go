@@ -0,0 +1,149 @@
+package graph
+
+import (
+	"sort"
+)
+
+// Connections with their weights, sortable by weight.
+type weightedConnections struct {
+	connections []Connection
+	weights []float64
+}
+
+func (c *weightedConnections) Less(i, j int) bool {
+	return c.weights[i] < c.weights[j]
+}
+
+func (c *weightedConnections) Swap(i, j int) {
+	c.connections[i], c.connections[j] = c.connections[j], c.connections[i]
+	c.weights[i], c.weights[j] = c.weights[j], c.weights[i]
+}
+
+func (c *weightedConnections) Len() int {
+	return len(c.connections)
+}
+
+// Minimum spanning tree with Kruskal algorithm.
+//
+// All edges are sorted by weight and added to tree one by one, skipping edges,
+// which make cycles. For disconnected graph minimum spanning forest is
+// returned. Use SimpleWeightFunc for unweighted graphs.
+//
+// Returns tree edges and their total weight.
+func Kruskal(gr UndirectedGraphEdgesReader, weightFunc ConnectionWeightFunc) ([]Connection, float64) {
+	edges := &weightedConnections{
+		connections: make([]Connection, 0, gr.EdgesCnt()),
+		weights: make([]float64, 0, gr.EdgesCnt()),
+	}
+	for conn := range gr.EdgesIter() {
+		edges.connections = append(edges.connections, conn)
+		edges.weights = append(edges.weights, weightFunc(conn.Tail, conn.Head))
+	}
+	sort.Sort(edges)
+	
+	sets := newVertexesDisjointSets()
+	tree := make([]Connection, 0, 10)
+	totalWeight := 0.0
+	for i, conn := range edges.connections {
+		if sets.Union(conn.Tail, conn.Head) {
+			tree = append(tree, conn)
+			totalWeight += edges.weights[i]
+		}
+	}
+	return tree, totalWeight
+}
+
+// Minimum bottleneck spanning tree: tree with minimal maximum edge weight.
+//
+// Any minimum spanning tree is also a minimum bottleneck one, so the tree is
+// built with Kruskal algorithm. For disconnected graph minimum bottleneck
+// spanning forest is returned.
+//
+// Returns tree edges and maximal weight among them. Bottleneck of tree without
+// edges is 0.
+func MinimumBottleneckSpanningTree(gr UndirectedGraphEdgesReader, weightFunc ConnectionWeightFunc) ([]Connection, float64) {
+	tree, _ := Kruskal(gr, weightFunc)
+	bottleneck := 0.0
+	for i, conn := range tree {
+		if weight := weightFunc(conn.Tail, conn.Head); i==0 || weight>bottleneck {
+			bottleneck = weight
+		}
+	}
+	return tree, bottleneck
+}
+
+// Minimum spanning tree with Prim algorithm.
+//
+// Tree grows from start node, on each step adding the edge with minimal weight
+// between tree and the rest of graph. For disconnected graph only the
+// component, containing start node, is spanned.
+//
+// Returns tree edges (tail is the vertex with smallest id, just like for
+// undirected connections) and their total weight.
+func Prim(gr UndirectedGraphEdgesReader, start VertexId, weightFunc ConnectionWeightFunc) ([]Connection, float64) {
+	// nodes, which are already in tree
+	inTree := make(map[VertexId]bool)
+	// minimal known edge weight from tree to node, and tree node of this edge
+	bestWeight := make(map[VertexId]float64)
+	bestTail := make(map[VertexId]VertexId)
+	
+	tree := make([]Connection, 0, 10)
+	totalWeight := 0.0
+	q := NewPriorityQueue(10)
+	q.Push(start, 0.0)
+	for q.Len()>0 {
+		curNode, _ := q.Pop()
+		inTree[curNode] = true
+		if curNode!=start {
+			tree = append(tree, NewUndirectedConnection(bestTail[curNode], curNode).Connection)
+			totalWeight += bestWeight[curNode]
+		}
+		for nextNode := range gr.GetNeighbours(curNode).VertexesIter() {
+			if _, ok := inTree[nextNode]; ok {
+				continue
+			}
+			edgeWeight := weightFunc(curNode, nextNode)
+			if knownWeight, ok := bestWeight[nextNode]; !ok || edgeWeight<knownWeight {
+				bestWeight[nextNode] = edgeWeight
+				bestTail[nextNode] = curNode
+				q.Push(nextNode, edgeWeight)
+			}
+		}
+	}
+	return tree, totalWeight
+}
+
+// Spanning tree (not necessarily minimal) with breadth-first search.
+//
+// Tree is rooted at root node. Neighbours are processed in increasing id
+// order, so result is the same for equal graphs. Tree edges are returned in
+// order of discovery, tail is the vertex with smallest id, just like for
+// undirected connections.
+//
+// Vertexes are taken from edges, so second result is true if all vertexes
+// of all edges are reachable from root, and false if graph is disconnected.
+func SpanningTree(gr UndirectedGraphEdgesReader, root VertexId) ([]Connection, bool) {
+	visited := map[VertexId]bool{root:true}
+	tree := make([]Connection, 0, 10)
+	queue := []VertexId{root}
+	for len(queue)>0 {
+		curNode := queue[0]
+		queue = queue[1:]
+		for _, nextNode := range SortedVertexes(gr.GetNeighbours(curNode)) {
+			if visited[nextNode] {
+				continue
+			}
+			visited[nextNode] = true
+			tree = append(tree, NewUndirectedConnection(curNode, nextNode).Connection)
+			queue = append(queue, nextNode)
+		}
+	}
+	
+	spanned := true
+	for conn := range gr.EdgesIter() {
+		if !visited[conn.Tail] || !visited[conn.Head] {
+			spanned = false
+		}
+	}
+	return tree, spanned
+}
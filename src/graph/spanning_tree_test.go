@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"testing"
+	"github.com/orfjackal/gospec/src/gospec"
+	. "github.com/orfjackal/gospec/src/gospec"
+)
+
+func KruskalSpec(c gospec.Context) {
+	gr, weightFunc := generateWeightedUndirectedGraph1()
+	
+	c.Specify("Connected graph", func() {
+		tree, weight := Kruskal(gr, weightFunc)
+		c.Expect(weight, Equals, 16.0)
+		c.Expect(len(tree), Equals, gr.Order()-1)
+	})
+	
+	c.Specify("Disconnected graph gives a forest", func() {
+		ReadUgraphLine(gr, "10-11-12")
+		tree, weight := Kruskal(gr, SimpleWeightFunc)
+		c.Expect(weight, Equals, 7.0)
+		c.Expect(len(tree), Equals, 7)
+	})
+}
+
+func MinimumBottleneckSpanningTreeSpec(c gospec.Context) {
+	c.Specify("Bottleneck is the largest tree edge", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-4-1")
+		ReadUgraphLine(gr, "1-3")
+		weightFunc := func(tail, head VertexId) float64 {
+			return float64(tail * head)
+		}
+		tree, bottleneck := MinimumBottleneckSpanningTree(gr, weightFunc)
+		c.Expect(len(tree), Equals, 3)
+		// 1-2, 1-3 and 1-4 are chosen, 2-3 and 3-4 are heavier
+		c.Expect(bottleneck, Equals, 4.0)
+		maxWeight := 0.0
+		for _, conn := range tree {
+			if weight := weightFunc(conn.Tail, conn.Head); weight>maxWeight {
+				maxWeight = weight
+			}
+		}
+		c.Expect(bottleneck, Equals, maxWeight)
+	})
+	
+	c.Specify("Graph without edges", func() {
+		tree, bottleneck := MinimumBottleneckSpanningTree(NewUndirectedMap(), SimpleWeightFunc)
+		c.Expect(len(tree), Equals, 0)
+		c.Expect(bottleneck, Equals, 0.0)
+	})
+}
+
+func PrimSpec(c gospec.Context) {
+	gr, weightFunc := generateWeightedUndirectedGraph1()
+	
+	c.Specify("Same weight as Kruskal", func() {
+		_, kruskalWeight := Kruskal(gr, weightFunc)
+		for start := range gr.VertexesIter() {
+			tree, weight := Prim(gr, start, weightFunc)
+			c.Expect(weight, Equals, kruskalWeight)
+			c.Expect(len(tree), Equals, gr.Order()-1)
+			for _, conn := range tree {
+				c.Expect(gr.CheckEdge(conn.Tail, conn.Head), IsTrue)
+			}
+		}
+	})
+	
+	c.Specify("Only start component is spanned", func() {
+		ReadUgraphLine(gr, "10-11-12")
+		tree, weight := Prim(gr, 11, SimpleWeightFunc)
+		c.Expect(weight, Equals, 2.0)
+		c.Expect(len(tree), Equals, 2)
+	})
+}
+
+func SpanningTreeSpec(c gospec.Context) {
+	c.Specify("Breadth-first tree of grid", func() {
+		// 0 1 2
+		// 3 4 5
+		gr := GridGraph(2, 3)
+		tree, spanned := SpanningTree(gr, 0)
+		c.Expect(spanned, IsTrue)
+		c.Expect(len(tree), Equals, gr.Order()-1)
+		treeStr := make([]string, len(tree))
+		for i, conn := range tree {
+			treeStr[i] = conn.String()
+		}
+		c.Expect(treeStr, ContainsInOrder, Values("0->1", "0->3", "1->2", "1->4", "2->5"))
+	})
+	
+	c.Specify("Disconnected graph", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3")
+		ReadUgraphLine(gr, "4-5")
+		tree, spanned := SpanningTree(gr, 2)
+		c.Expect(spanned, IsFalse)
+		c.Expect(len(tree), Equals, 2)
+	})
+}
+
+func TestSpanningTree(t *testing.T) {
+	r := gospec.NewRunner()
+	r.AddSpec(KruskalSpec)
+	r.AddSpec(MinimumBottleneckSpanningTreeSpec)
+	r.AddSpec(PrimSpec)
+	r.AddSpec(SpanningTreeSpec)
+	gospec.MainGoTest(r, t)
+}
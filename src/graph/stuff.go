@@ -124,6 +124,9 @@ func newPriorityQueueSimple(initialSize int) *nodesPriorityQueueSimple {
 }
 
 // Add new item to queue
+//
+// If node is already in the queue, then it's priority is changed only if new
+// priority is greater than the old one.
 func (q *nodesPriorityQueueSimple) Add(node VertexId, priority float64) {
 	defer func() {
 		if e := recover(); e!=nil {
@@ -134,46 +137,38 @@ func (q *nodesPriorityQueueSimple) Add(node VertexId, priority float64) {
 		}
 	}()
 	
-	found := false
 	if id, ok := q.nodesIndex[node]; ok {
-		if priority > q.data[id].Priority { 
-			q.data[id].Priority = priority
-			// changing position
-			newId := id+1
-			for q.data[newId].Priority<priority && newId<q.size {
-				newId++
-			}
-			
-			if newId > id+1 {
-				// need to move
-				copy(q.data[id:newId-1], q.data[id+1:newId])
-				q.data[newId-1].Node = node
-				q.data[newId-1].Priority = priority
+		if priority > q.data[id].Priority {
+			// changing position: moving all nodes with lower priority down
+			for id+1<q.size && q.data[id+1].Priority<priority {
+				q.data[id] = q.data[id+1]
+				q.nodesIndex[q.data[id].Node] = id
+				id++
 			}
+			q.data[id].Node = node
+			q.data[id].Priority = priority
+			q.nodesIndex[node] = id
 		}
-		found = true
+		return
 	}
 
-	if !found {
-		if q.size==len(q.data) {
-			// resize
-			// 2 is just a magic number
-			newData := make(nodesPriority, 2*len(q.data))
-			copy(newData, q.data)
-			q.data = newData
-		}
-		id := 0
-		for q.data[id].Priority<priority && id<q.size {
-			id++
-		}
-		if id<q.size {
-			copy(q.data[id+1:q.size+1], q.data[id:q.size])
-		}
-		q.data[id].Node = node
-		q.data[id].Priority = priority
-		q.nodesIndex[node] = id
-		q.size++
+	if q.size==len(q.data) {
+		// resize
+		// 2 is just a magic number
+		newData := make(nodesPriority, 2*len(q.data))
+		copy(newData, q.data)
+		q.data = newData
+	}
+	id := q.size
+	for id>0 && q.data[id-1].Priority>=priority {
+		q.data[id] = q.data[id-1]
+		q.nodesIndex[q.data[id].Node] = id
+		id--
 	}
+	q.data[id].Node = node
+	q.data[id].Priority = priority
+	q.nodesIndex[node] = id
+	q.size++
 }
 
 // Get item with max priority and remove it from the queue
@@ -186,6 +181,7 @@ func (q *nodesPriorityQueueSimple) Next() (VertexId, float64) {
 	node := q.data[q.size-1].Node
 	prior := q.data[q.size-1].Priority
 	q.size--
+	q.nodesIndex[node] = 0, false
 	
 	return node, prior
 }
@@ -212,6 +208,376 @@ func (q *nodesPriorityQueueSimple) Empty() bool {
 	return q.Size()==0
 }
 
+type priority_heap_item_t struct {
+	Node VertexId
+	Priority float64
+	seq int // insertion order for items with equal priority
+}
+
+// Nodes priority queue, based on binary heap
+//
+// Add and Next take O(log(n)) time. Each node is stored only once: adding node,
+// which is already in queue, raises it's priority in place (that's the
+// decrease-key operation for searches with inverted weights), so queue never
+// contains stale items. Items with equal priorities are extracted in order of
+// addition, just like in nodesPriorityQueueSimple.
+type nodesPriorityQueueHeap struct {
+	data []priority_heap_item_t
+	nodesIndex map[VertexId]int
+	nextSeq int
+}
+
+// Create new heap nodes priority queue
+//
+// initialSize is the initial capacity, queue grows automatically.
+func newPriorityQueueHeap(initialSize int) *nodesPriorityQueueHeap {
+	if initialSize<=0 {
+		err := erx.NewError("Can't create priority queue with non-positive size.")
+		err.AddV("size", initialSize)
+		panic(err)
+	}
+	
+	q := &nodesPriorityQueueHeap {
+		data: make([]priority_heap_item_t, 0, initialSize),
+		nodesIndex: make(map[VertexId]int),
+		nextSeq: 0,
+	}
+	return q
+}
+
+// Add new item to queue
+//
+// If node is already in the queue, then it's priority is changed only if new
+// priority is greater than the old one.
+func (q *nodesPriorityQueueHeap) Add(node VertexId, priority float64) {
+	if id, ok := q.nodesIndex[node]; ok {
+		if priority > q.data[id].Priority {
+			q.data[id].Priority = priority
+			q.data[id].seq = q.nextSeq
+			q.nextSeq++
+			q.up(id)
+		}
+		return
+	}
+	
+	q.data = append(q.data, priority_heap_item_t{Node:node, Priority:priority, seq:q.nextSeq})
+	q.nextSeq++
+	id := len(q.data)-1
+	q.nodesIndex[node] = id
+	q.up(id)
+}
+
+// Get item with max priority and remove it from the queue
+//
+// Panic if queue is empty
+func (q *nodesPriorityQueueHeap) Next() (VertexId, float64) {
+	if q.Empty() {
+		panic("Can't pick from empty queue.")
+	}
+	top := q.data[0]
+	last := len(q.data)-1
+	q.swap(0, last)
+	q.data = q.data[0:last]
+	q.nodesIndex[top.Node] = 0, false
+	if last>0 {
+		q.down(0)
+	}
+	return top.Node, top.Priority
+}
+
+// Get item with max priority without removing it from the queue
+//
+// Panic if queue is empty
+func (q *nodesPriorityQueueHeap) Pick() (VertexId, float64) {
+	if q.Empty() {
+		panic("Can't pick from empty queue.")
+	}
+	return q.data[0].Node, q.data[0].Priority
+}
+
+// Total queue size
+func (q *nodesPriorityQueueHeap) Size() int {
+	return len(q.data)
+}
+
+// Check if queue is empty
+func (q *nodesPriorityQueueHeap) Empty() bool {
+	return q.Size()==0
+}
+
+// Remove all items from queue, keeping allocated memory
+func (q *nodesPriorityQueueHeap) Clear() {
+	for _, item := range q.data {
+		q.nodesIndex[item.Node] = 0, false
+	}
+	q.data = q.data[0:0]
+	q.nextSeq = 0
+}
+
+// Check if item i must be extracted before item j.
+func (q *nodesPriorityQueueHeap) before(i, j int) bool {
+	if q.data[i].Priority!=q.data[j].Priority {
+		return q.data[i].Priority>q.data[j].Priority
+	}
+	return q.data[i].seq<q.data[j].seq
+}
+
+func (q *nodesPriorityQueueHeap) swap(i, j int) {
+	q.data[i], q.data[j] = q.data[j], q.data[i]
+	q.nodesIndex[q.data[i].Node] = i
+	q.nodesIndex[q.data[j].Node] = j
+}
+
+func (q *nodesPriorityQueueHeap) up(id int) {
+	for id>0 {
+		parent := (id-1)/2
+		if !q.before(id, parent) {
+			break
+		}
+		q.swap(id, parent)
+		id = parent
+	}
+}
+
+func (q *nodesPriorityQueueHeap) down(id int) {
+	for {
+		best := id
+		if left := 2*id+1; left<len(q.data) && q.before(left, best) {
+			best = left
+		}
+		if right := 2*id+2; right<len(q.data) && q.before(right, best) {
+			best = right
+		}
+		if best==id {
+			break
+		}
+		q.swap(id, best)
+		id = best
+	}
+}
+
+// Set item priority to any value, moving it up or down in the heap.
+//
+// Panic if node isn't in the queue.
+func (q *nodesPriorityQueueHeap) set(node VertexId, priority float64) {
+	id, ok := q.nodesIndex[node]
+	if !ok {
+		err := erx.NewError("Node isn't in queue.")
+		err.AddV("node", node)
+		panic(err)
+	}
+	q.data[id].Priority = priority
+	q.data[id].seq = q.nextSeq
+	q.nextSeq++
+	q.up(id)
+	q.down(q.nodesIndex[node])
+}
+
+// Vertexes priority queue with minimal (or maximal) priority first
+//
+// Binary heap, which is used by search algorithms in this package, so Push and
+// Pop take O(log(n)) time, and each node is stored only once. Vertexes with
+// equal priorities are extracted in order of addition.
+type PriorityQueue struct {
+	heap *nodesPriorityQueueHeap
+	// heap is max-first, so priorities are multiplied by -1 for min-first queue
+	sign float64
+}
+
+// Create empty priority queue with minimal priority first
+//
+// initialSize is the initial capacity, queue grows automatically.
+func NewPriorityQueue(initialSize int) *PriorityQueue {
+	return &PriorityQueue{heap:newPriorityQueueHeap(initialSize), sign:-1.0}
+}
+
+// Create empty priority queue with maximal priority first
+//
+// Works exactly like queue from NewPriorityQueue with all comparisons
+// reversed: Push raises priority of node, which is already in queue, and Pop
+// and Peek return node with maximal priority.
+func NewMaxPriorityQueue(initialSize int) *PriorityQueue {
+	return &PriorityQueue{heap:newPriorityQueueHeap(initialSize), sign:1.0}
+}
+
+// Add node to queue
+//
+// If node is already in the queue, then it's priority is changed only if new
+// priority is less than the old one (greater for max-first queue), which is
+// the decrease-key operation in Dijkstra-like searches.
+func (q *PriorityQueue) Push(node VertexId, priority float64) {
+	q.heap.Add(node, q.sign * priority)
+}
+
+// Get node with minimal (or maximal) priority and remove it from the queue
+//
+// Panic if queue is empty
+func (q *PriorityQueue) Pop() (VertexId, float64) {
+	node, priority := q.heap.Next()
+	return node, q.sign * priority
+}
+
+// Get node with minimal (or maximal) priority without removing it from the queue
+//
+// Panic if queue is empty
+func (q *PriorityQueue) Peek() (VertexId, float64) {
+	node, priority := q.heap.Pick()
+	return node, q.sign * priority
+}
+
+// Change priority of node in queue, it may be both less or greater than old one
+//
+// Panic if node isn't in queue
+func (q *PriorityQueue) Update(node VertexId, priority float64) {
+	q.heap.set(node, q.sign * priority)
+}
+
+// Check if node is in queue
+func (q *PriorityQueue) Contains(node VertexId) bool {
+	_, ok := q.heap.nodesIndex[node]
+	return ok
+}
+
+// Nodes count in queue
+func (q *PriorityQueue) Len() int {
+	return q.heap.Size()
+}
+
+// Remove all nodes from queue, keeping allocated memory
+func (q *PriorityQueue) Clear() {
+	q.heap.Clear()
+}
+
+func (nodes Vertexes) Less(i, j int) bool {
+	return nodes[i] < nodes[j]
+}
+
+func (nodes Vertexes) Swap(i, j int) {
+	nodes[i], nodes[j] = nodes[j], nodes[i]
+}
+
+func (nodes Vertexes) Len() int {
+	return len(nodes)
+}
+
+// Disjoint sets of vertexes (union-find structure)
+//
+// Uses path compression and union by rank. Vertexes are added to structure
+// as single element sets on first access.
+//
+// Note: internal use only!
+type vertexesDisjointSets struct {
+	parent map[VertexId]VertexId
+	rank map[VertexId]int
+}
+
+func newVertexesDisjointSets() *vertexesDisjointSets {
+	return &vertexesDisjointSets {
+		parent: make(map[VertexId]VertexId),
+		rank: make(map[VertexId]int),
+	}
+}
+
+// Get representative vertex of set, which contains given node
+func (s *vertexesDisjointSets) Find(node VertexId) VertexId {
+	root := node
+	for {
+		parent, ok := s.parent[root]
+		if !ok {
+			// new single element set
+			s.parent[root] = root
+			s.rank[root] = 0
+			break
+		}
+		if parent==root {
+			break
+		}
+		root = parent
+	}
+	
+	// path compression
+	for node!=root {
+		next := s.parent[node]
+		s.parent[node] = root
+		node = next
+	}
+	return root
+}
+
+// Merge sets, which contain node1 and node2
+//
+// Returns false if nodes are already in the same set.
+func (s *vertexesDisjointSets) Union(node1, node2 VertexId) bool {
+	root1 := s.Find(node1)
+	root2 := s.Find(node2)
+	if root1==root2 {
+		return false
+	}
+	if s.rank[root1]<s.rank[root2] {
+		root1, root2 = root2, root1
+	}
+	s.parent[root2] = root1
+	if s.rank[root1]==s.rank[root2] {
+		s.rank[root1]++
+	}
+	return true
+}
+
+// Double-ended queue of vertexes, based on ring buffer
+//
+// Note: internal use only!
+type vertexesDeque struct {
+	data []VertexId
+	head int
+	size int
+}
+
+func newVertexesDeque(initialSize int) *vertexesDeque {
+	return &vertexesDeque{data: make([]VertexId, initialSize)}
+}
+
+func (d *vertexesDeque) grow() {
+	if d.size<len(d.data) {
+		return
+	}
+	data := make([]VertexId, 2*len(d.data)+1)
+	for i:=0; i<d.size; i++ {
+		data[i] = d.data[(d.head+i)%len(d.data)]
+	}
+	d.data = data
+	d.head = 0
+}
+
+func (d *vertexesDeque) PushFront(node VertexId) {
+	d.grow()
+	d.head = (d.head + len(d.data) - 1) % len(d.data)
+	d.data[d.head] = node
+	d.size++
+}
+
+func (d *vertexesDeque) PushBack(node VertexId) {
+	d.grow()
+	d.data[(d.head+d.size)%len(d.data)] = node
+	d.size++
+}
+
+// Get node from front of deque and remove it
+//
+// Panic if deque is empty
+func (d *vertexesDeque) PopFront() VertexId {
+	if d.size==0 {
+		panic("Can't pop from empty deque.")
+	}
+	node := d.data[d.head]
+	d.head = (d.head + 1) % len(d.data)
+	d.size--
+	return node
+}
+
+func (d *vertexesDeque) Size() int {
+	return d.size
+}
+
 // Index function for matrix storage.
 //
 // node1, node2 - vertexes
@@ -282,3 +648,43 @@ func matrixConnectionsIndexer(node1, node2 VertexId, vertexIds map[VertexId]int,
 	connId := id1*(size-1) + id2 - 1 - id1*(id1+1)/2
 	return connId 
 }
+
+// Edge in adjacency list: neighbour node and edge id.
+type adjacentEdge struct {
+	node VertexId
+	edgeId int
+}
+
+// Adjacency lists of undirected graph, built from it's edges.
+//
+// Each edge gets unique id in [0, edgesCnt) range and is added to both nodes
+// adjacency lists, so parallel edges are distinguishable. Returns adjacency
+// lists and edges count. Isolated nodes are absent in adjacency map.
+func undirectedEdgesAdjacency(gr UndirectedGraphEdgesReader) (map[VertexId][]adjacentEdge, int) {
+	adjacent := make(map[VertexId][]adjacentEdge)
+	edgesCnt := 0
+	for conn := range gr.EdgesIter() {
+		adjacent[conn.Tail] = append(adjacent[conn.Tail], adjacentEdge{conn.Head, edgesCnt})
+		adjacent[conn.Head] = append(adjacent[conn.Head], adjacentEdge{conn.Tail, edgesCnt})
+		edgesCnt++
+	}
+	return adjacent, edgesCnt
+}
+
+// Sort connections by tail and then by head.
+type connectionsByNodes []Connection
+
+func (c connectionsByNodes) Len() int {
+	return len(c)
+}
+
+func (c connectionsByNodes) Less(i, j int) bool {
+	if c[i].Tail!=c[j].Tail {
+		return c[i].Tail<c[j].Tail
+	}
+	return c[i].Head<c[j].Head
+}
+
+func (c connectionsByNodes) Swap(i, j int) {
+	c[i], c[j] = c[j], c[i]
+}
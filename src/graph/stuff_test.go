@@ -1,14 +1,58 @@
 package graph
 
 import (
+	"rand"
 	"testing"
 	"github.com/orfjackal/gospec/src/gospec"
 	. "github.com/orfjackal/gospec/src/gospec"
 )
 
 func VertexesPriorityQueueSpec(c gospec.Context) {
-	q := newPriorityQueueSimple(5)
+	vertexesPriorityQueueSpec(c, newPriorityQueueSimple(5))
+}
+
+func VertexesPriorityQueueHeapSpec(c gospec.Context) {
+	vertexesPriorityQueueSpec(c, newPriorityQueueHeap(5))
+	
+	c.Specify("Same order as in simple queue", func() {
+		q := newPriorityQueueHeap(5)
+		qSimple := newPriorityQueueSimple(5)
+		r := rand.New(rand.NewSource(42))
+		for i:=0; i<1000; i++ {
+			if r.Intn(3)==0 && !q.Empty() {
+				node, prior := q.Next()
+				nodeSimple, priorSimple := qSimple.Next()
+				c.Expect(node, Equals, nodeSimple)
+				c.Expect(prior, Equals, priorSimple)
+			} else {
+				node := VertexId(r.Intn(50))
+				prior := float64(r.Intn(20))
+				q.Add(node, prior)
+				qSimple.Add(node, prior)
+			}
+			c.Expect(q.Size(), Equals, qSimple.Size())
+		}
+	})
 	
+	c.Specify("Cleared queue is empty and reusable", func() {
+		q := newPriorityQueueHeap(5)
+		q.Add(1, 1.0)
+		q.Add(2, 3.0)
+		q.Clear()
+		c.Expect(q.Empty(), IsTrue)
+		q.Add(1, 0.5)
+		q.Add(3, 2.0)
+		node, prior := q.Next()
+		c.Expect(node, Equals, VertexId(3))
+		c.Expect(prior, Equals, 2.0)
+		node, prior = q.Next()
+		c.Expect(node, Equals, VertexId(1))
+		c.Expect(prior, Equals, 0.5)
+		c.Expect(q.Empty(), IsTrue)
+	})
+}
+
+func vertexesPriorityQueueSpec(c gospec.Context, q nodesPriorityQueue) {
 	c.Specify("Empty queue", func() {
 		c.Specify("is empty", func() {
 			c.Expect(q.Empty(), IsTrue)
@@ -128,6 +172,28 @@ func VertexesPriorityQueueSpec(c gospec.Context) {
 		})
 	})
 	
+	c.Specify("Add node again after extracting it", func() {
+		q.Add(VertexId(1), 1.0)
+		q.Add(VertexId(2), 2.0)
+		q.Add(VertexId(3), 3.0)
+		
+		node, _ := q.Next()
+		c.Expect(node, Equals, VertexId(3))
+		q.Add(VertexId(3), 0.5)
+		q.Add(VertexId(1), 2.5)
+		
+		c.Expect(q.Size(), Equals, 3)
+		node, prior := q.Next()
+		c.Expect(node, Equals, VertexId(1))
+		c.Expect(prior, Equals, 2.5)
+		node, prior = q.Next()
+		c.Expect(node, Equals, VertexId(2))
+		c.Expect(prior, Equals, 2.0)
+		node, prior = q.Next()
+		c.Expect(node, Equals, VertexId(3))
+		c.Expect(prior, Equals, 0.5)
+	})
+	
 	c.Specify("Push more items than initial size", func() {
 		n1 := VertexId(1)
 		p1 := float64(1.0)
@@ -172,6 +238,107 @@ func VertexesPriorityQueueSpec(c gospec.Context) {
 	})
 }
 
+func PriorityQueueSpec(c gospec.Context) {
+	q := NewPriorityQueue(2)
+	q.Push(1, 3.0)
+	q.Push(2, 1.0)
+	q.Push(3, 2.0)
+	q.Push(4, 2.0)
+	
+	c.Specify("Minimal priority first, equal ones in order of addition", func() {
+		c.Expect(q.Len(), Equals, 4)
+		node, prior := q.Peek()
+		c.Expect(node, Equals, VertexId(2))
+		c.Expect(prior, Equals, 1.0)
+		for _, expected := range []VertexId{2, 3, 4, 1} {
+			node, _ := q.Pop()
+			c.Expect(node, Equals, expected)
+		}
+		c.Expect(q.Len(), Equals, 0)
+	})
+	
+	c.Specify("Push decreases priority only", func() {
+		q.Push(1, 0.5)
+		q.Push(2, 5.0)
+		node, prior := q.Pop()
+		c.Expect(node, Equals, VertexId(1))
+		c.Expect(prior, Equals, 0.5)
+		node, prior = q.Pop()
+		c.Expect(node, Equals, VertexId(2))
+		c.Expect(prior, Equals, 1.0)
+	})
+	
+	c.Specify("Update changes priority both ways", func() {
+		q.Update(2, 10.0)
+		q.Update(1, 0.0)
+		c.Expect(q.Contains(2), IsTrue)
+		for _, expected := range []VertexId{1, 3, 4, 2} {
+			node, _ := q.Pop()
+			c.Expect(node, Equals, expected)
+		}
+		c.Expect(q.Contains(2), IsFalse)
+	})
+	
+	c.Specify("Same order as sorting", func() {
+		r := rand.New(rand.NewSource(7))
+		q := NewPriorityQueue(5)
+		priorities := make(map[VertexId]float64)
+		for i:=0; i<200; i++ {
+			node := VertexId(r.Intn(100))
+			prior := float64(r.Intn(1000))
+			if q.Contains(node) {
+				q.Update(node, prior)
+			} else {
+				q.Push(node, prior)
+			}
+			priorities[node] = prior
+		}
+		c.Expect(q.Len(), Equals, len(priorities))
+		last := -1.0
+		for q.Len()>0 {
+			node, prior := q.Pop()
+			c.Expect(prior, Equals, priorities[node])
+			c.Expect(prior >= last, IsTrue)
+			last = prior
+		}
+	})
+	
+	c.Specify("Max-first queue", func() {
+		q := NewMaxPriorityQueue(5)
+		q.Push(1, 2.0)
+		q.Push(2, 5.0)
+		q.Push(3, 1.0)
+		// priority is raised, but never lowered by Push
+		q.Push(3, 7.0)
+		q.Push(2, 0.5)
+		node, prior := q.Peek()
+		c.Expect(node, Equals, VertexId(3))
+		c.Expect(prior, Equals, 7.0)
+		q.Update(1, 10.0)
+		expected := []VertexId{1, 3, 2}
+		for _, expectedNode := range expected {
+			node, _ := q.Pop()
+			c.Expect(node, Equals, expectedNode)
+		}
+	})
+}
+
+func VertexesDequeSpec(c gospec.Context) {
+	d := newVertexesDeque(2)
+	d.PushBack(2)
+	d.PushFront(1)
+	d.PushBack(3)
+	d.PushFront(0)
+	d.PushBack(4)
+	c.Expect(d.Size(), Equals, 5)
+	for i:=0; i<5; i++ {
+		c.Expect(d.PopFront(), Equals, VertexId(i))
+	}
+	c.Expect(d.Size(), Equals, 0)
+	d.PushFront(5)
+	c.Expect(d.PopFront(), Equals, VertexId(5))
+}
+
 func MatrixIndexerSpec(c gospec.Context) {
 	size := 100
 	usedIds := make(map[int]bool)
@@ -186,9 +353,31 @@ func MatrixIndexerSpec(c gospec.Context) {
 	}
 }
 
+func VertexesDisjointSetsSpec(c gospec.Context) {
+	sets := newVertexesDisjointSets()
+	
+	c.Specify("New node is a single element set", func() {
+		c.Expect(sets.Find(VertexId(1)), Equals, VertexId(1))
+		c.Expect(sets.Find(VertexId(2)), Equals, VertexId(2))
+	})
+	
+	c.Specify("Merged nodes have same representative", func() {
+		c.Expect(sets.Union(1, 2), IsTrue)
+		c.Expect(sets.Union(3, 4), IsTrue)
+		c.Expect(sets.Union(2, 4), IsTrue)
+		c.Expect(sets.Union(1, 3), IsFalse)
+		c.Expect(sets.Find(VertexId(1)), Equals, sets.Find(VertexId(4)))
+		c.Expect(sets.Find(VertexId(5))!=sets.Find(VertexId(1)), IsTrue)
+	})
+}
+
 func TestStuff(t *testing.T) {
 	r := gospec.NewRunner()
 	r.AddSpec(VertexesPriorityQueueSpec)
+	r.AddSpec(VertexesPriorityQueueHeapSpec)
+	r.AddSpec(PriorityQueueSpec)
+	r.AddSpec(VertexesDequeSpec)
 	r.AddSpec(MatrixIndexerSpec)
+	r.AddSpec(VertexesDisjointSetsSpec)
 	gospec.MainGoTest(r, t)
 }
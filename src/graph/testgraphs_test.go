@@ -62,3 +62,101 @@ func genUgr2IndependentSubGr() (UndirectedGraphReader, UndirectedGraphReader, Un
 	
 	return gr1, gr2, gr_merged
 }
+
+// Connection of weighted test graph.
+type weightedConnection struct {
+	tail, head VertexId
+	weight float64
+}
+
+// Directed graph with given weighted arcs.
+func genWeightedDgraph(arcs ...weightedConnection) (DirectedGraph, ConnectionWeightFunc) {
+	gr := NewDirectedMap()
+	weights := make(map[VertexId]map[VertexId]float64)
+	for _, arc := range arcs {
+		gr.AddArc(arc.tail, arc.head)
+		if _, ok := weights[arc.tail]; !ok {
+			weights[arc.tail] = make(map[VertexId]float64)
+		}
+		weights[arc.tail][arc.head] = arc.weight
+	}
+	
+	return gr, func(tail, head VertexId) float64 {
+		return weights[tail][head]
+	}
+}
+
+// Undirected graph with given weighted edges. Weight function is symmetric.
+func genWeightedUgraph(edges ...weightedConnection) (UndirectedGraph, ConnectionWeightFunc) {
+	gr := NewUndirectedMap()
+	weights := make(map[VertexId]map[VertexId]float64)
+	for _, edge := range edges {
+		gr.AddEdge(edge.tail, edge.head)
+		if _, ok := weights[edge.tail]; !ok {
+			weights[edge.tail] = make(map[VertexId]float64)
+		}
+		if _, ok := weights[edge.head]; !ok {
+			weights[edge.head] = make(map[VertexId]float64)
+		}
+		weights[edge.tail][edge.head] = edge.weight
+		weights[edge.head][edge.tail] = edge.weight
+	}
+	
+	return gr, func(tail, head VertexId) float64 {
+		return weights[tail][head]
+	}
+}
+
+// Weighted undirected graph.
+//
+// Minimum spanning tree weight is 16: 1-2(1), 2-4(2), 3-4(3), 4-5(4), 5-6(6).
+func generateWeightedUndirectedGraph1() (UndirectedGraph, ConnectionWeightFunc) {
+	return genWeightedUgraph(
+		weightedConnection{1, 2, 1.0},
+		weightedConnection{1, 3, 5.0},
+		weightedConnection{2, 3, 7.0},
+		weightedConnection{2, 4, 2.0},
+		weightedConnection{3, 4, 3.0},
+		weightedConnection{4, 5, 4.0},
+		weightedConnection{3, 5, 8.0},
+		weightedConnection{5, 6, 6.0},
+		weightedConnection{4, 6, 9.0},
+	)
+}
+
+// Weighted directed graph from Yen algorithm example, vertexes C, D, E, F, G
+// and H are numbered from 1 to 6.
+//
+// Two shortest paths from 1 to 6 are 1>3>4>6(5) and 1>3>5>6(7).
+func generateWeightedDirectedGraph1() (DirectedGraph, ConnectionWeightFunc) {
+	return genWeightedDgraph(
+		weightedConnection{1, 2, 3.0},
+		weightedConnection{1, 3, 2.0},
+		weightedConnection{2, 4, 4.0},
+		weightedConnection{3, 2, 1.0},
+		weightedConnection{3, 4, 2.0},
+		weightedConnection{3, 5, 3.0},
+		weightedConnection{4, 5, 2.0},
+		weightedConnection{4, 6, 1.0},
+		weightedConnection{5, 6, 2.0},
+	)
+}
+
+// Flow network from Cormen et al. "Introduction to Algorithms", with
+// source 1 and sink 6.
+//
+// Maximum flow value is 23.
+func generateFlowNetwork1() (DirectedGraph, ConnectionWeightFunc) {
+	return genWeightedDgraph(
+		weightedConnection{1, 2, 16.0},
+		weightedConnection{1, 3, 13.0},
+		weightedConnection{2, 3, 10.0},
+		weightedConnection{3, 2, 4.0},
+		weightedConnection{2, 4, 12.0},
+		weightedConnection{4, 3, 9.0},
+		weightedConnection{3, 5, 14.0},
+		weightedConnection{5, 4, 7.0},
+		weightedConnection{4, 6, 20.0},
+		weightedConnection{5, 6, 4.0},
+	)
+}
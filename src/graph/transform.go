@@ -0,0 +1,277 @@
+package graph
+
+import (
+	"sort"
+
+	"github.com/StepLg/go-erx/src/erx"
+)
+
+// Transposed copy of directed graph.
+//
+// Result is new DirectedMap with all vertexes of original graph (including
+// isolated ones) and every arc reversed. Original graph isn't changed.
+func Transpose(gr DirectedGraphReader) DirectedGraph {
+	res := NewDirectedMap()
+	for node := range gr.VertexesIter() {
+		res.AddNode(node)
+	}
+	for conn := range gr.ArcsIter() {
+		res.AddArc(conn.Head, conn.Tail)
+	}
+	return res
+}
+
+// Subgraph of directed graph, induced by set of vertexes.
+//
+// Result is new DirectedMap with vertexes, which are both in original graph and
+// in vertexes set (with true value), and all arcs between them. Vertexes from
+// set, which are absent in original graph, are ignored.
+func InducedSubgraph(gr DirectedGraphReader, vertexes map[VertexId]bool) DirectedGraph {
+	res := NewDirectedMap()
+	for node := range gr.VertexesIter() {
+		if vertexes[node] {
+			res.AddNode(node)
+		}
+	}
+	for conn := range gr.ArcsIter() {
+		if vertexes[conn.Tail] && vertexes[conn.Head] {
+			res.AddArc(conn.Tail, conn.Head)
+		}
+	}
+	return res
+}
+
+// Subgraph of undirected graph, induced by set of vertexes.
+//
+// Works like InducedSubgraph, but for undirected graph. Result is new
+// UndirectedMap.
+func InducedSubgraphUndirected(gr UndirectedGraphReader, vertexes map[VertexId]bool) UndirectedGraph {
+	res := NewUndirectedMap()
+	for node := range gr.VertexesIter() {
+		if vertexes[node] {
+			res.AddNode(node)
+		}
+	}
+	for conn := range gr.EdgesIter() {
+		if vertexes[conn.Tail] && vertexes[conn.Head] {
+			res.AddEdge(conn.Tail, conn.Head)
+		}
+	}
+	return res
+}
+
+// Copy of directed graph without single vertex.
+//
+// Result is new DirectedMap with all vertexes except node, and all arcs, which
+// don't touch node. Original graph isn't changed. If there is no such node in
+// graph, then result is just a copy.
+func WithoutVertex(gr DirectedGraphReader, node VertexId) DirectedGraph {
+	res := NewDirectedMap()
+	for curNode := range gr.VertexesIter() {
+		if curNode!=node {
+			res.AddNode(curNode)
+		}
+	}
+	for conn := range gr.ArcsIter() {
+		if conn.Tail!=node && conn.Head!=node {
+			res.AddArc(conn.Tail, conn.Head)
+		}
+	}
+	return res
+}
+
+// Copy of directed graph without single arc.
+//
+// Result is new DirectedMap with all vertexes (including tail and head) and
+// all arcs except tail->head. Original graph isn't changed. If there is no
+// such arc in graph, then result is just a copy.
+func WithoutArc(gr DirectedGraphReader, tail, head VertexId) DirectedGraph {
+	res := NewDirectedMap()
+	for node := range gr.VertexesIter() {
+		res.AddNode(node)
+	}
+	for conn := range gr.ArcsIter() {
+		if conn.Tail!=tail || conn.Head!=head {
+			res.AddArc(conn.Tail, conn.Head)
+		}
+	}
+	return res
+}
+
+// Contract edge between u and v, merging v into u.
+//
+// Graph is modified in place: every edge of v becomes edge of u, and then v is
+// removed. Edge u-v (and self-loop on v) becomes self-loop on u only if
+// keepLoops is true, otherwise it's dropped. Edges to nodes, which are
+// neighbours of both u and v, are merged into single edge. u and v don't have
+// to be connected, in this case they are just merged.
+func ContractEdge(gr UndirectedGraph, u, v VertexId, keepLoops bool) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			err := erx.NewSequent("Contract edge", e)
+			err.AddV("u", u)
+			err.AddV("v", v)
+			panic(err)
+		}
+	}()
+	
+	if u==v {
+		err := erx.NewError("Can't merge node with itself.")
+		panic(err)
+	}
+	if !gr.CheckNode(u) {
+		err := erx.NewError("Node doesn't exist.")
+		err.AddV("node", u)
+		panic(err)
+	}
+	
+	neighbours := CollectVertexes(gr.GetNeighbours(v))
+	gr.RemoveNode(v)
+	for _, node := range neighbours {
+		if node==u || node==v {
+			if keepLoops && !gr.CheckEdge(u, u) {
+				gr.AddEdge(u, u)
+			}
+			continue
+		}
+		if !gr.CheckEdge(u, node) {
+			gr.AddEdge(u, node)
+		}
+	}
+}
+
+// Line graph of undirected graph.
+//
+// Each edge of original graph becomes vertex of line graph, and two vertexes
+// are connected, if their edges share an endpoint. Edges are sorted by their
+// nodes, just like undirected connections, and get vertex ids from 0 to edges
+// count - 1 in this order.
+//
+// Returns new UndirectedMap and map from each line graph vertex to original
+// edge.
+func LineGraph(gr UndirectedGraphEdgesReader) (UndirectedGraph, map[VertexId]Connection) {
+	edges := make([]Connection, 0, gr.EdgesCnt())
+	for conn := range gr.EdgesIter() {
+		edges = append(edges, NewUndirectedConnection(conn.Tail, conn.Head).Connection)
+	}
+	sort.Sort(connectionsByNodes(edges))
+	
+	res := NewUndirectedMap()
+	edgeOf := make(map[VertexId]Connection, len(edges))
+	// line graph vertexes of edges for each original node
+	incident := make(map[VertexId][]VertexId)
+	for i, conn := range edges {
+		lineNode := VertexId(i)
+		res.AddNode(lineNode)
+		edgeOf[lineNode] = conn
+		incident[conn.Tail] = append(incident[conn.Tail], lineNode)
+		if conn.Head!=conn.Tail {
+			incident[conn.Head] = append(incident[conn.Head], lineNode)
+		}
+	}
+	for _, lineNodes := range incident {
+		for i:=0; i<len(lineNodes); i++ {
+			for j:=i+1; j<len(lineNodes); j++ {
+				if !res.CheckEdge(lineNodes[i], lineNodes[j]) {
+					res.AddEdge(lineNodes[i], lineNodes[j])
+				}
+			}
+		}
+	}
+	return res, edgeOf
+}
+
+// Compress chains of degree 2 vertexes in undirected graph.
+//
+// Interior vertex has exactly two different neighbours and no self-loop. Each
+// maximal chain of interior vertexes between two other (surviving) vertexes is
+// replaced with single edge, whose weight is the sum of chain edges weights.
+// If there are several chains or edges between the same surviving vertexes,
+// then only the lightest one is kept, and chains from surviving vertex back
+// to itself are dropped. So shortest paths weights between surviving vertexes
+// are the same as in original graph (for non-negative weights), but interior
+// vertexes are gone, and result is useless if they are queries targets.
+// Cycles, which consist of interior vertexes only, are copied unchanged.
+//
+// Returns new UndirectedMap and weight function for it, which is defined only
+// for it's edges. Original graph isn't changed.
+func SimplifyDegree2Chains(gr UndirectedGraphReader, weightFunc ConnectionWeightFunc) (UndirectedGraph, ConnectionWeightFunc) {
+	neighbours := make(map[VertexId][]VertexId)
+	interior := make(map[VertexId]bool)
+	for node := range gr.VertexesIter() {
+		neighbours[node] = CollectVertexes(gr.GetNeighbours(node))
+		nodeNeighbours := neighbours[node]
+		interior[node] = len(nodeNeighbours)==2 && nodeNeighbours[0]!=nodeNeighbours[1] &&
+			nodeNeighbours[0]!=node && nodeNeighbours[1]!=node
+	}
+	
+	res := NewUndirectedMap()
+	// weights of result edges, tail is not greater than head
+	weights := make(map[VertexId]map[VertexId]float64)
+	addEdge := func(node1, node2 VertexId, weight float64) {
+		edge := NewUndirectedConnection(node1, node2).Connection
+		if _, ok := weights[edge.Tail]; !ok {
+			weights[edge.Tail] = make(map[VertexId]float64)
+		}
+		if knownWeight, ok := weights[edge.Tail][edge.Head]; ok {
+			if weight<knownWeight {
+				weights[edge.Tail][edge.Head] = weight
+			}
+			return
+		}
+		weights[edge.Tail][edge.Head] = weight
+		res.AddEdge(edge.Tail, edge.Head)
+	}
+	
+	// interior vertexes, which are in chains between surviving vertexes
+	inChain := make(map[VertexId]bool)
+	for node, nodeNeighbours := range neighbours {
+		if interior[node] {
+			continue
+		}
+		if !res.CheckNode(node) {
+			res.AddNode(node)
+		}
+		for _, nextNode := range nodeNeighbours {
+			if !interior[nextNode] {
+				// edges between surviving vertexes are added from the smaller end
+				if node<=nextNode {
+					addEdge(node, nextNode, weightFunc(node, nextNode))
+				}
+				continue
+			}
+			prevNode, curNode := node, nextNode
+			weight := weightFunc(prevNode, curNode)
+			for interior[curNode] {
+				inChain[curNode] = true
+				nextNode := neighbours[curNode][0]
+				if nextNode==prevNode {
+					nextNode = neighbours[curNode][1]
+				}
+				weight += weightFunc(curNode, nextNode)
+				prevNode, curNode = curNode, nextNode
+			}
+			// each chain is found from both ends
+			if node<curNode {
+				addEdge(node, curNode, weight)
+			}
+		}
+	}
+	
+	for node := range interior {
+		if !interior[node] || inChain[node] {
+			continue
+		}
+		for _, nextNode := range neighbours[node] {
+			if node<nextNode {
+				addEdge(node, nextNode, weightFunc(node, nextNode))
+			}
+		}
+	}
+	
+	resWeightFunc := func(tail, head VertexId) float64 {
+		edge := NewUndirectedConnection(tail, head).Connection
+		return weights[edge.Tail][edge.Head]
+	}
+	return res, resWeightFunc
+}
@@ -0,0 +1,218 @@
+package graph
+
+import (
+	"testing"
+	"github.com/orfjackal/gospec/src/gospec"
+	. "github.com/orfjackal/gospec/src/gospec"
+)
+
+func TransposeSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	gr.AddNode(10)
+	
+	c.Specify("Arcs are reversed", func() {
+		trGr := Transpose(gr)
+		c.Expect(trGr.Order(), Equals, gr.Order())
+		c.Expect(trGr.ArcsCnt(), Equals, gr.ArcsCnt())
+		c.Expect(trGr.CheckNode(10), IsTrue)
+		c.Expect(trGr.CheckArc(2, 1), IsTrue)
+		c.Expect(trGr.CheckArc(1, 2), IsFalse)
+		c.Expect(CollectVertexes(trGr.GetAccessors(4)), ContainsExactly, Values(VertexId(2), VertexId(3)))
+	})
+	
+	c.Specify("Original graph isn't changed", func() {
+		Transpose(gr)
+		c.Expect(gr.CheckArc(1, 2), IsTrue)
+		c.Expect(gr.CheckArc(2, 1), IsFalse)
+	})
+	
+	c.Specify("Transposing twice gives original graph", func() {
+		c.Expect(DirectedGraphsEquals(Transpose(Transpose(gr)), gr), IsTrue)
+	})
+}
+
+func InducedSubgraphSpec(c gospec.Context) {
+	c.Specify("Directed graph", func() {
+		gr := generateDirectedGraph1()
+		subGr := InducedSubgraph(gr, map[VertexId]bool{2:true, 3:true, 4:true, 6:false, 100:true})
+		c.Expect(CollectVertexes(subGr), ContainsExactly, Values(VertexId(2), VertexId(3), VertexId(4)))
+		c.Expect(subGr.ArcsCnt(), Equals, 3)
+		c.Expect(subGr.CheckArc(2, 3), IsTrue)
+		c.Expect(subGr.CheckArc(3, 4), IsTrue)
+		c.Expect(subGr.CheckArc(2, 4), IsTrue)
+		c.Expect(gr.ArcsCnt(), Equals, 7)
+	})
+	
+	c.Specify("Undirected graph", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-4-1")
+		gr.AddNode(5)
+		subGr := InducedSubgraphUndirected(gr, map[VertexId]bool{1:true, 2:true, 4:true, 5:true})
+		c.Expect(CollectVertexes(subGr), ContainsExactly, Values(VertexId(1), VertexId(2), VertexId(4), VertexId(5)))
+		c.Expect(subGr.EdgesCnt(), Equals, 2)
+		c.Expect(subGr.CheckEdge(1, 2), IsTrue)
+		c.Expect(subGr.CheckEdge(4, 1), IsTrue)
+	})
+}
+
+func WithoutVertexSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	
+	c.Specify("Vertex and it's arcs are removed", func() {
+		res := WithoutVertex(gr, 2)
+		c.Expect(res.Order(), Equals, 5)
+		c.Expect(res.CheckNode(2), IsFalse)
+		c.Expect(res.ArcsCnt(), Equals, 3)
+		expected := NewDirectedMap()
+		ReadDgraphLine(expected, "3>4>5")
+		ReadDgraphLine(expected, "1>6")
+		c.Expect(Equal(res, expected), IsTrue)
+	})
+	
+	c.Specify("Isolated vertexes are kept", func() {
+		res := WithoutVertex(gr, 6)
+		c.Expect(res.Order(), Equals, 5)
+		res = WithoutVertex(res, 3)
+		res = WithoutVertex(res, 2)
+		c.Expect(res.Order(), Equals, 3)
+		c.Expect(CollectVertexes(res), ContainsExactly, Values(VertexId(1), VertexId(4), VertexId(5)))
+	})
+	
+	c.Specify("Original graph is untouched", func() {
+		WithoutVertex(gr, 2)
+		WithoutArc(gr, 4, 5)
+		c.Expect(Equal(gr, generateDirectedGraph1()), IsTrue)
+	})
+	
+	c.Specify("Single arc is removed", func() {
+		res := WithoutArc(gr, 4, 5)
+		c.Expect(res.Order(), Equals, 6)
+		c.Expect(res.ArcsCnt(), Equals, 6)
+		c.Expect(res.CheckArc(4, 5), IsFalse)
+		c.Expect(CollectVertexes(res.GetPredecessors(5)), ContainsExactly, Values())
+		c.Expect(Equal(WithoutArc(gr, 5, 4), gr), IsTrue)
+	})
+}
+
+func ContractEdgeSpec(c gospec.Context) {
+	gr := NewUndirectedMap()
+	ReadUgraphLine(gr, "1-2-3-4")
+	ReadUgraphLine(gr, "2-5")
+	ReadUgraphLine(gr, "3-6")
+	degreesSum := func() int {
+		sum := 0
+		for node := range gr.VertexesIter() {
+			sum += Degree(gr, node)
+		}
+		return sum
+	}
+	
+	c.Specify("Degrees sum is decreased by contracted edge", func() {
+		sumBefore := degreesSum()
+		edgesBefore := gr.EdgesCnt()
+		ContractEdge(gr, 2, 3, false)
+		c.Expect(gr.CheckNode(3), IsFalse)
+		c.Expect(gr.Order(), Equals, 5)
+		c.Expect(gr.EdgesCnt(), Equals, edgesBefore-1)
+		c.Expect(degreesSum(), Equals, sumBefore-2)
+		c.Expect(CollectVertexes(gr.GetNeighbours(2)), ContainsExactly, Values(VertexId(1), VertexId(4), VertexId(5), VertexId(6)))
+	})
+	
+	c.Specify("Contracted edge is kept as self-loop", func() {
+		edgesBefore := gr.EdgesCnt()
+		ContractEdge(gr, 2, 3, true)
+		c.Expect(gr.EdgesCnt(), Equals, edgesBefore)
+		c.Expect(gr.CheckEdge(2, 2), IsTrue)
+	})
+	
+	c.Specify("Common neighbours edges are merged", func() {
+		ReadUgraphLine(gr, "1-3")
+		ContractEdge(gr, 2, 3, false)
+		c.Expect(gr.EdgesCnt(), Equals, 4)
+		c.Expect(gr.CheckEdge(1, 2), IsTrue)
+	})
+}
+
+func LineGraphSpec(c gospec.Context) {
+	c.Specify("Path of 3 edges", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-4")
+		lineGr, edgeOf := LineGraph(gr)
+		c.Expect(lineGr.Order(), Equals, 3)
+		c.Expect(lineGr.EdgesCnt(), Equals, 2)
+		c.Expect(edgeOf[0].String(), Equals, "1->2")
+		c.Expect(edgeOf[1].String(), Equals, "2->3")
+		c.Expect(edgeOf[2].String(), Equals, "3->4")
+		c.Expect(CollectVertexes(lineGr.GetNeighbours(1)), ContainsExactly, Values(VertexId(0), VertexId(2)))
+	})
+	
+	c.Specify("Star becomes triangle", func() {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2")
+		ReadUgraphLine(gr, "1-3")
+		ReadUgraphLine(gr, "1-4")
+		lineGr, _ := LineGraph(gr)
+		c.Expect(lineGr.Order(), Equals, 3)
+		c.Expect(lineGr.EdgesCnt(), Equals, 3)
+	})
+}
+
+func SimplifyDegree2ChainsSpec(c gospec.Context) {
+	weightFunc := func(tail, head VertexId) float64 {
+		return float64(tail + head)
+	}
+	
+	c.Specify("Chains are replaced with edges", func() {
+		gr := NewUndirectedMap()
+		// star center 1 with chains to 5 and 8, and direct edge to 9
+		ReadUgraphLine(gr, "1-2-3-4-5")
+		ReadUgraphLine(gr, "1-6-7-8")
+		ReadUgraphLine(gr, "1-9")
+		res, resWeightFunc := SimplifyDegree2Chains(gr, weightFunc)
+		c.Expect(CollectVertexes(res), ContainsExactly, Values(VertexId(1), VertexId(5), VertexId(8), VertexId(9)))
+		c.Expect(res.EdgesCnt(), Equals, 3)
+		c.Expect(res.CheckEdge(1, 5), IsTrue)
+		c.Expect(resWeightFunc(1, 5), Equals, 3.0 + 5.0 + 7.0 + 9.0)
+		c.Expect(resWeightFunc(8, 1), Equals, 7.0 + 13.0 + 15.0)
+		c.Expect(resWeightFunc(1, 9), Equals, 10.0)
+	})
+	
+	c.Specify("Shortest paths between surviving vertexes are kept", func() {
+		gr := NewUndirectedMap()
+		// two chains and direct edge between 1 and 5, and tail 5-6
+		ReadUgraphLine(gr, "1-2-3-5")
+		ReadUgraphLine(gr, "1-4-5")
+		ReadUgraphLine(gr, "1-5-6")
+		ReadUgraphLine(gr, "6-7-8-6")
+		res, resWeightFunc := SimplifyDegree2Chains(gr, SimpleWeightFunc)
+		c.Expect(res.CheckEdge(1, 5), IsTrue)
+		c.Expect(resWeightFunc(1, 5), Equals, 1.0)
+		// loop 6-7-8-6 is dropped
+		c.Expect(res.Order(), Equals, 3)
+		for _, from := range CollectVertexes(res) {
+			for _, to := range CollectVertexes(res) {
+				expected, _ := CheckPathDijkstra(NewUgraphOutNeighboursExtractor(gr), from, to, nil, SimpleWeightFunc)
+				weight, _ := CheckPathDijkstra(NewUgraphOutNeighboursExtractor(res), from, to, nil, resWeightFunc)
+				c.Expect(weight, Equals, expected)
+			}
+		}
+	})
+	
+	c.Specify("Cycle of degree 2 vertexes is copied", func() {
+		res, resWeightFunc := SimplifyDegree2Chains(CycleGraph(4), weightFunc)
+		c.Expect(res.Order(), Equals, 4)
+		c.Expect(res.EdgesCnt(), Equals, 4)
+		c.Expect(resWeightFunc(1, 2), Equals, 3.0)
+	})
+}
+
+func TestTransform(t *testing.T) {
+	r := gospec.NewRunner()
+	r.AddSpec(TransposeSpec)
+	r.AddSpec(InducedSubgraphSpec)
+	r.AddSpec(WithoutVertexSpec)
+	r.AddSpec(ContractEdgeSpec)
+	r.AddSpec(LineGraphSpec)
+	r.AddSpec(SimplifyDegree2ChainsSpec)
+	gospec.MainGoTest(r, t)
+}
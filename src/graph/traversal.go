@@ -0,0 +1,192 @@
+package graph
+
+import (
+	"rand"
+)
+
+// Visit all nodes, reachable from given one, in breadth-first order.
+//
+// visitFunc is called exactly once for each reachable node (including from
+// node) in order of increasing distance (in arcs) from from node.
+func BreadthFirstVisit(neighboursExtractor OutNeighboursExtractor, from VertexId, visitFunc func(node VertexId)) {
+	// nodes, which were already added to queue
+	visited := make(map[VertexId]bool)
+	visited[from] = true
+	queue := make([]VertexId, 0, 10)
+	queue = append(queue, from)
+	
+	for len(queue)>0 {
+		curNode := queue[0]
+		queue = queue[1:]
+		visitFunc(curNode)
+		for nextNode := range neighboursExtractor.GetOutNeighbours(curNode).VertexesIter() {
+			if _, ok := visited[nextNode]; !ok {
+				visited[nextNode] = true
+				queue = append(queue, nextNode)
+			}
+		}
+	}
+	return
+}
+
+// Iterate over all nodes, reachable from given one, in breadth-first order.
+//
+// Channel variant of BreadthFirstVisit. Channel is closed after the last node.
+//
+// Warning! Caller must read channel till the end, otherwise goroutine will
+// block forever. Use BreadthFirstVisit if you may need to stop earlier.
+func BreadthFirstWalk(neighboursExtractor OutNeighboursExtractor, from VertexId) <-chan VertexId {
+	ch := make(chan VertexId)
+	go func() {
+		BreadthFirstVisit(neighboursExtractor, from, func(node VertexId) {
+			ch <- node
+		})
+		close(ch)
+	}()
+	return ch
+}
+
+// Distances (in connections count) from given node to all reachable nodes.
+//
+// Breadth-first search with explicit queue. from node has distance 0,
+// unreachable nodes are absent in result map.
+func BFSDistances(neighboursExtractor OutNeighboursExtractor, from VertexId) map[VertexId]int {
+	// distances map is also a set of nodes, which were already added to queue
+	dist := make(map[VertexId]int)
+	dist[from] = 0
+	queue := make([]VertexId, 0, 10)
+	queue = append(queue, from)
+	
+	for len(queue)>0 {
+		curNode := queue[0]
+		queue = queue[1:]
+		for nextNode := range neighboursExtractor.GetOutNeighbours(curNode).VertexesIter() {
+			if _, ok := dist[nextNode]; !ok {
+				dist[nextNode] = dist[curNode] + 1
+				queue = append(queue, nextNode)
+			}
+		}
+	}
+	return dist
+}
+
+// Set of all nodes, reachable from given one, including from node itself.
+func ReachableSet(neighboursExtractor OutNeighboursExtractor, from VertexId) map[VertexId]bool {
+	reachable := make(map[VertexId]bool)
+	BreadthFirstVisit(neighboursExtractor, from, func(node VertexId) {
+		reachable[node] = true
+	})
+	return reachable
+}
+
+// Check if to node is reachable from from node.
+//
+// Breadth-first search stops as soon as to node is found. Node is always
+// reachable from itself.
+func CanReach(neighboursExtractor OutNeighboursExtractor, from, to VertexId) bool {
+	if from==to {
+		return true
+	}
+	visited := make(map[VertexId]bool)
+	visited[from] = true
+	queue := make([]VertexId, 0, 10)
+	queue = append(queue, from)
+	
+	for len(queue)>0 {
+		curNode := queue[0]
+		queue = queue[1:]
+		found := false
+		for nextNode := range neighboursExtractor.GetOutNeighbours(curNode).VertexesIter() {
+			if found {
+				// just reading neighbours till the end
+				continue
+			}
+			if nextNode==to {
+				found = true
+			} else if _, ok := visited[nextNode]; !ok {
+				visited[nextNode] = true
+				queue = append(queue, nextNode)
+			}
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// Stack frame for iterative depth-first search.
+type dfsFrame struct {
+	node VertexId
+	neighbours []VertexId
+	pos int // position of next neighbour to process
+}
+
+// Visit all nodes, reachable from given one, in depth-first order.
+//
+// onEnter is called when node is discovered, and onLeave is called when all
+// nodes, reachable from it, are processed. Any of them may be nil.
+//
+// Search is iterative, so it works fine on very deep graphs.
+func DepthFirstWalk(neighboursExtractor OutNeighboursExtractor, from VertexId, onEnter, onLeave func(node VertexId)) {
+	// all discovered nodes
+	visited := make(map[VertexId]bool)
+	stack := make([]dfsFrame, 0, 10)
+	
+	enter := func(node VertexId) {
+		visited[node] = true
+		if onEnter!=nil {
+			onEnter(node)
+		}
+		stack = append(stack, dfsFrame{
+			node: node,
+			neighbours: CollectVertexes(neighboursExtractor.GetOutNeighbours(node)),
+			pos: 0,
+		})
+	}
+	
+	enter(from)
+	for len(stack)>0 {
+		top := &stack[len(stack)-1]
+		if top.pos==len(top.neighbours) {
+			// all accessible nodes are processed
+			if onLeave!=nil {
+				onLeave(top.node)
+			}
+			stack = stack[0:len(stack)-1]
+			continue
+		}
+		nextNode := top.neighbours[top.pos]
+		top.pos++
+		if _, ok := visited[nextNode]; !ok {
+			enter(nextNode)
+		}
+	}
+	return
+}
+
+// Random walk from given node.
+//
+// On each of steps steps walk goes to uniformly random out neighbour of
+// current node, so node with several equal neighbours (parallel connections)
+// is chosen proportionally. If current node has no neighbours, then walk stops
+// earlier. Non-positive steps count gives walk of start node only.
+//
+// Returns visited nodes sequence, starting with start node, so its length is
+// at most steps+1. Neighbours are sorted before choosing, so seeded rng gives
+// reproducible walks. Nil rng means package-level default generator, which
+// isn't safe for concurrent use, so pass own rng to each goroutine.
+func RandomWalk(neighboursExtractor OutNeighboursExtractor, start VertexId, steps int, rng *rand.Rand) []VertexId {
+	if rng==nil {
+		rng = defaultGeneratorRand
+	}
+	walk := []VertexId{start}
+	for i:=0; i<steps; i++ {
+		neighbours := SortedVertexes(neighboursExtractor.GetOutNeighbours(walk[len(walk)-1]))
+		if len(neighbours)==0 {
+			break
+		}
+		walk = append(walk, neighbours[rng.Intn(len(neighbours))])
+	}
+	return walk
+}
@@ -0,0 +1,165 @@
+package graph
+
+import (
+	"rand"
+	"testing"
+	"github.com/orfjackal/gospec/src/gospec"
+	. "github.com/orfjackal/gospec/src/gospec"
+)
+
+func BreadthFirstWalkSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	extractor := NewDgraphOutNeighboursExtractor(gr)
+	
+	c.Specify("Each reachable node visited once in distance order", func() {
+		dist := map[VertexId]int{1: 0, 2: 1, 6: 1, 3: 2, 4: 2, 5: 3}
+		visited := make([]VertexId, 0)
+		for node := range BreadthFirstWalk(extractor, 1) {
+			visited = append(visited, node)
+		}
+		c.Expect(visited, ContainsExactly, Values(VertexId(1), VertexId(2), VertexId(3), VertexId(4), VertexId(5), VertexId(6)))
+		for i:=1; i<len(visited); i++ {
+			c.Expect(dist[visited[i-1]] <= dist[visited[i]], IsTrue)
+		}
+	})
+	
+	c.Specify("Unreachable nodes aren't visited", func() {
+		visited := make([]VertexId, 0)
+		BreadthFirstVisit(extractor, 4, func(node VertexId) {
+			visited = append(visited, node)
+		})
+		c.Expect(visited, ContainsInOrder, Values(VertexId(4), VertexId(5)))
+	})
+}
+
+func BFSDistancesSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	extractor := NewDgraphOutNeighboursExtractor(gr)
+	
+	c.Specify("Distances to all reachable nodes", func() {
+		dist := BFSDistances(extractor, 1)
+		c.Expect(len(dist), Equals, 6)
+		c.Expect(dist[1], Equals, 0)
+		c.Expect(dist[2], Equals, 1)
+		c.Expect(dist[6], Equals, 1)
+		c.Expect(dist[3], Equals, 2)
+		c.Expect(dist[4], Equals, 2)
+		c.Expect(dist[5], Equals, 3)
+	})
+	
+	c.Specify("Unreachable nodes are absent", func() {
+		dist := BFSDistances(extractor, 3)
+		c.Expect(len(dist), Equals, 3)
+		_, ok := dist[1]
+		c.Expect(ok, IsFalse)
+		c.Expect(dist[5], Equals, 2)
+	})
+}
+
+func ReachableSetSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	extractor := NewDgraphOutNeighboursExtractor(gr)
+	
+	c.Specify("Reachable set includes node itself", func() {
+		reachable := ReachableSet(extractor, 3)
+		c.Expect(len(reachable), Equals, 3)
+		c.Expect(reachable[3], IsTrue)
+		c.Expect(reachable[4], IsTrue)
+		c.Expect(reachable[5], IsTrue)
+	})
+	
+	c.Specify("Reachability check", func() {
+		c.Expect(CanReach(extractor, 1, 5), IsTrue)
+		c.Expect(CanReach(extractor, 6, 6), IsTrue)
+		c.Expect(CanReach(extractor, 5, 1), IsFalse)
+		c.Expect(CanReach(extractor, 3, 6), IsFalse)
+	})
+}
+
+func DepthFirstWalkSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	extractor := NewDgraphOutNeighboursExtractor(gr)
+	
+	c.Specify("Node is left after all it's accessors", func() {
+		entered := make(map[VertexId]int)
+		left := make(map[VertexId]int)
+		step := 0
+		DepthFirstWalk(extractor, 1, func(node VertexId) {
+			entered[node] = step
+			step++
+		}, func(node VertexId) {
+			left[node] = step
+			step++
+		})
+		c.Expect(len(entered), Equals, 6)
+		c.Expect(len(left), Equals, 6)
+		for conn := range gr.ArcsIter() {
+			c.Expect(left[conn.Head] < left[conn.Tail], IsTrue)
+		}
+		for node, enterStep := range entered {
+			c.Expect(enterStep < left[node], IsTrue)
+		}
+	})
+	
+	c.Specify("Callbacks may be nil", func() {
+		visited := make([]VertexId, 0)
+		DepthFirstWalk(extractor, 4, nil, func(node VertexId) {
+			visited = append(visited, node)
+		})
+		c.Expect(visited, ContainsInOrder, Values(VertexId(5), VertexId(4)))
+		DepthFirstWalk(extractor, 4, nil, nil)
+	})
+	
+	c.Specify("Deep chain", func() {
+		chain := NewDirectedMap()
+		nodesCnt := 100000
+		for i:=1; i<nodesCnt; i++ {
+			chain.AddArc(VertexId(i-1), VertexId(i))
+		}
+		leftCnt := 0
+		DepthFirstWalk(NewDgraphOutNeighboursExtractor(chain), 0, nil, func(node VertexId) {
+			leftCnt++
+		})
+		c.Expect(leftCnt, Equals, nodesCnt)
+	})
+}
+
+func RandomWalkSpec(c gospec.Context) {
+	gr := generateDirectedGraph1()
+	extractor := NewDgraphOutNeighboursExtractor(gr)
+	
+	c.Specify("Each step goes by arc", func() {
+		ugr := GridGraph(5, 5)
+		walk := RandomWalk(NewUgraphOutNeighboursExtractor(ugr), 12, 50, rand.New(rand.NewSource(3)))
+		c.Expect(len(walk), Equals, 51)
+		c.Expect(walk[0], Equals, VertexId(12))
+		for i:=1; i<len(walk); i++ {
+			c.Expect(ugr.CheckEdge(walk[i-1], walk[i]), IsTrue)
+		}
+	})
+	
+	c.Specify("Same walk with same seed", func() {
+		walk1 := RandomWalk(extractor, 1, 10, rand.New(rand.NewSource(5)))
+		walk2 := RandomWalk(extractor, 1, 10, rand.New(rand.NewSource(5)))
+		c.Expect(len(walk1), Equals, len(walk2))
+		c.Expect(pathsEqual(walk1, walk2), IsTrue)
+	})
+	
+	c.Specify("Walk stops in node without accessors", func() {
+		walk := RandomWalk(extractor, 4, 10, nil)
+		c.Expect(walk, ContainsInOrder, Values(VertexId(4), VertexId(5)))
+		c.Expect(len(walk), Equals, 2)
+		c.Expect(len(RandomWalk(extractor, 1, 0, nil)), Equals, 1)
+		c.Expect(len(RandomWalk(extractor, 1, -5, nil)), Equals, 1)
+	})
+}
+
+func TestTraversal(t *testing.T) {
+	r := gospec.NewRunner()
+	r.AddSpec(BreadthFirstWalkSpec)
+	r.AddSpec(BFSDistancesSpec)
+	r.AddSpec(ReachableSetSpec)
+	r.AddSpec(DepthFirstWalkSpec)
+	r.AddSpec(RandomWalkSpec)
+	gospec.MainGoTest(r, t)
+}